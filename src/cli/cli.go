@@ -3,16 +3,128 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
+	"face_recognition/image"
 	r "face_recognition/recognition"
 )
 
+// loads the face mask given on the command line. "ellipse" creates an elliptical mask
+// for the 92x112 images of the data set, anything else is used as a path to a PGM mask file
+// masked pixels are dropped from the vectors. Returns the mask or an error
+func LoadMask(value string) (*image.Mask, error) {
+	if value == "ellipse" {
+		mask, err := image.EllipseMask(112, 92, 0.9, 0.95, true)
+		if err != nil {
+			return nil, err
+		}
+		return &mask, nil
+	}
+
+	mask, err := image.LoadPgmMask(value, true)
+	if err != nil {
+		return nil, err
+	}
+	return &mask, nil
+}
+
+// parses the eigenvector backend given on the command line, "symmetric", "qr", "svd", "jacobi",
+// "lanczos", "subspace" or "rsvd"
+// returns the backend or an error for unknown names
+func ParseBackend(value string) (r.Backend, error) {
+	switch value {
+	case "symmetric":
+		return r.BackendSymmetric, nil
+	case "qr":
+		return r.BackendQR, nil
+	case "svd":
+		return r.BackendSVD, nil
+	case "jacobi":
+		return r.BackendJacobi, nil
+	case "lanczos":
+		return r.BackendLanczos, nil
+	case "subspace":
+		return r.BackendSubspace, nil
+	case "rsvd":
+		return r.BackendRandomizedSVD, nil
+	}
+	return 0, fmt.Errorf("unknown backend %q, use symmetric, qr, svd, jacobi, lanczos, subspace or rsvd", value)
+}
+
+// parses the recognition method given on the command line, "eigenfaces", "kpca" or "2dpca"
+// returns the method or an error for unknown names
+func ParseMethod(value string) (r.Method, error) {
+	switch value {
+	case "eigenfaces":
+		return r.MethodEigenfaces, nil
+	case "kpca":
+		return r.MethodKernelPCA, nil
+	case "2dpca":
+		return r.MethodTwoDPCA, nil
+	}
+	return 0, fmt.Errorf("unknown method %q, use eigenfaces, kpca or 2dpca", value)
+}
+
+// parses the kernel of kernel PCA given on the command line as "rbf[:gamma]" or
+// "poly[:degree[:coef0[:gamma]]]". Missing parameters are chosen like in r.Kernel,
+// except that coef0 of the polynomial kernel defaults to 1
+// returns the kernel or an error
+func ParseKernel(value string) (r.Kernel, error) {
+	parts := strings.Split(value, ":")
+	params := make([]float64, len(parts)-1)
+	for i, part := range parts[1:] {
+		param, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return r.Kernel{}, err
+		}
+		params[i] = param
+	}
+
+	switch parts[0] {
+	case "rbf":
+		if len(params) > 1 {
+			return r.Kernel{}, fmt.Errorf("too many parameters in kernel %q", value)
+		}
+		kernel := r.Kernel{Type: r.KernelRBF}
+		if len(params) > 0 {
+			kernel.Gamma = params[0]
+		}
+		return kernel, nil
+	case "poly":
+		if len(params) > 3 {
+			return r.Kernel{}, fmt.Errorf("too many parameters in kernel %q", value)
+		}
+		kernel := r.Kernel{Type: r.KernelPolynomial, Coef0: 1}
+		if len(params) > 0 {
+			// the degree is parsed again as an integer so 2.5 isn't truncated to 2
+			degree, err := strconv.Atoi(parts[1])
+			if err != nil || degree < 1 {
+				return r.Kernel{}, fmt.Errorf("degree of kernel %q must be a positive integer", value)
+			}
+			kernel.Degree = degree
+		}
+		if len(params) > 1 {
+			kernel.Coef0 = params[1]
+		}
+		if len(params) > 2 {
+			kernel.Gamma = params[2]
+		}
+		return kernel, nil
+	}
+	return r.Kernel{}, fmt.Errorf("unknown kernel %q, use rbf or poly", parts[0])
+}
+
 // prints usage instructions and available command-line options for the program.
 func Help() {
 	fmt.Println(`
 usage:
     ./face_recognition [options]
     ./face_recognition     # without any options this will use interactive cli mode
+    ./face_recognition detect <image.pgm> [options]   # find faces in a larger image, see "detect -h"
+    ./face_recognition reconstruct [options]          # compress an image with eigenfaces, see "reconstruct -h"
+    ./face_recognition spectrum [options]             # show the eigenvalues to help choosing -k, see "spectrum -h"
+    ./face_recognition evaluate [options]             # compare the accuracy of the recognition methods, see "evaluate -h"
 
 options:
     -h             shows this help message and terminates
@@ -21,6 +133,13 @@ options:
     -s <num num>   specify the test image to be used. Given as tuple <number number> where the first number is the set being used (1-40) and the second number which image is used (1-10)		   
     -i <num>       specify how many images are loaded from each set. Each set has 10 images.
     -d <num ...>   specify training datasets to use (e.g., 1 2 3). By default two random sets are used.
+    -a <seed>      add mirrored, rotated, shifted and jittered copies of each training image. The seed makes the random jitter reproducible.
+    -m <mask>      mask out background and hair. <mask> is "ellipse" or a path to a PGM mask file where bright pixels are kept.
+    -f             compute in single precision (float32). Uses half the memory with practically the same results.
+    -e <file.npz>  save the eigenfaces, mean face and projected training faces into a NumPy .npz file. Only eigenface models can be saved.
+    -b <backend>   how the eigenfaces are computed: "symmetric" (default) reduces the covariance matrix to tridiagonal form and runs shifted QR on it, "qr" runs the slower unshifted QR algorithm, "svd" uses the singular value decomposition of the training data which is the most accurate and "jacobi" diagonalizes the covariance matrix with Jacobi rotations which gives very accurate eigenvectors. "lanczos", "subspace" and "rsvd" compute only the first k eigenfaces with Lanczos iteration, block power iteration or randomized SVD, which is much faster with large training sets. kpca and 2dpca can't use "svd" or "rsvd".
+    -r <method>    recognition method: "eigenfaces" (default), "kpca", which runs PCA with a nonlinear kernel and can find structure of pose and expression that eigenfaces miss, or "2dpca", which computes the covariance from the image matrices without flattening them and works well with few training images. With 2dpca k is the number of projection axes (at most 92).
+    -g <kernel>    kernel of kpca: "rbf[:gamma]" (default) or "poly[:degree[:coef0[:gamma]]]". By default gamma is chosen from the training data, the degree is 2 and coef0 is 1.
 
 note 1: Using too high a value for k can reduce accuracy due to overfitting and noise. Lower k values often generalize better.
 note 2: Using too many training images / sets will lead to slow performance. I recommend using less than 10 full data sets / 100 images in total.
@@ -33,13 +152,17 @@ examples:
     ./face_recognition -d 1 2 3            # Use datasets 1, 2 and 3
     ./face_recognition -s 5 5              # Use set 5 image 5 as the test image
     ./face_recognition -k 8 -d 1 2 3 4 5   # Use 8 eigenfaces with datasets 1-5
+    ./face_recognition -m ellipse          # Use only the pixels inside an ellipse around the face
+    ./face_recognition -i 1 -a 42          # Use one image per set and augment it with seed 42
+    ./face_recognition -r kpca -g poly:3   # Use kernel PCA with a cubic polynomial kernel
 	`)
 }
 
 // provides an interactive CLI for configuring and running face recognition program
 // users can change parameters, select datasets, test images, and run the algorithm
 // the function is an infinite loop until cmd "quit" is given
-func Interactive(dataSets, testImage []int, k, imagesFromEachSet int, timing bool) {
+func Interactive(dataSets, testImage []int, k, imagesFromEachSet int, timing bool, opts r.Options) {
+	mask := "none"
 	for {
 		fmt.Println("\ncurrent settings:")
 		fmt.Println("-----------------------------------")
@@ -48,6 +171,8 @@ func Interactive(dataSets, testImage []int, k, imagesFromEachSet int, timing boo
 		fmt.Println("  test image (s):        ", testImage)
 		fmt.Println("  images per set:        ", imagesFromEachSet)
 		fmt.Println("  time algorithm steps:  ", timing)
+		fmt.Println("  mask (m):              ", mask)
+		fmt.Println("  augmentation (a):      ", opts.Augment != nil)
 		fmt.Println("-----------------------------------")
 		fmt.Println("\navailable commands:")
 		fmt.Println("  k    - change number of eigenfaces")
@@ -55,6 +180,8 @@ func Interactive(dataSets, testImage []int, k, imagesFromEachSet int, timing boo
 		fmt.Println("  s    - select test image")
 		fmt.Println("  t    - toggle timing")
 		fmt.Println("  i    - specify amount of images to use from each set")
+		fmt.Println("  m    - select mask (ellipse, path to PGM file or none)")
+		fmt.Println("  a    - toggle augmentation of the training images")
 		fmt.Println("  run  - run the algoritm")
 		fmt.Println("  quit - terminate program")
 
@@ -137,9 +264,42 @@ func Interactive(dataSets, testImage []int, k, imagesFromEachSet int, timing boo
 				}
 				fmt.Println("  invalid number")
 			}
+		case "m": // select mask
+			fmt.Print("  enter mask (ellipse, path to PGM file or none): ")
+			var value string
+			if _, err := fmt.Scan(&value); err != nil {
+				panic(err)
+			}
+			if value == "none" {
+				opts.Mask = nil
+				mask = value
+				continue
+			}
+
+			newMask, err := LoadMask(value)
+			if err != nil {
+				fmt.Println("  invalid mask:", err)
+				continue
+			}
+			opts.Mask = newMask
+			mask = value
+		case "a": // toggle augmentation
+			if opts.Augment != nil {
+				opts.Augment = nil
+				fmt.Print("augmentation set to: false")
+				continue
+			}
+
+			fmt.Print("  enter seed for augmentation: ")
+			var seed int64
+			if _, err := fmt.Scan(&seed); err != nil {
+				panic(err)
+			}
+			augmentation := image.DefaultAugmentation(seed)
+			opts.Augment = &augmentation
 		case "run": // run the algoritm and print out results
 			fmt.Print("\n###############################\n\n")
-			matchIndex, similarity, err := r.Run(timing, dataSets, testImage[:2], k, imagesFromEachSet, "./")
+			matchIndex, similarity, err := r.RunWithOptions(timing, dataSets, testImage[:2], k, imagesFromEachSet, "./", opts)
 			if err != nil {
 				fmt.Println(err)
 				continue
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"face_recognition/detection"
+	"face_recognition/image"
+	r "face_recognition/recognition"
+)
+
+// size of the detection window. The training faces are scaled down to this size
+const (
+	windowRows = 28
+	windowCols = 23
+)
+
+// prints usage instructions of the detect command
+func DetectHelp() {
+	fmt.Println(`
+usage:
+    ./face_recognition detect <image.pgm> [options]
+
+options:
+    -h             shows this help message and terminates
+    -k <num>       sets the number of eigenfaces used to score the windows. The default value is 10.
+    -d <num ...>   specify the data sets used to build the face space (e.g., 1 2 3). By default sets 1-10 are used.
+    -i <num>       specify how many images are loaded from each set. The default value is 5.
+    -t <num>       sets the highest accepted distance from face space per pixel. The default value is 0.65.
+    -c <file.xml>  find the faces with an OpenCV Haar cascade (e.g. haarcascade_frontalface_default.xml) instead of the face space
+    -r             recognize every detected face with eigenfaces trained once on the same data sets, -k and -i
+
+examples:
+    ./face_recognition detect photo.pgm              # Print the faces found in photo.pgm
+    ./face_recognition detect photo.pgm -t 0.5 -r    # Use a stricter threshold and recognize the faces
+    ./face_recognition detect photo.pgm -c haarcascade_frontalface_default.xml -r
+	`)
+}
+
+// runs the sliding window face detector on a PGM image given on the command line
+// prints the detected boxes and optionally the closest match of the best box
+func Detect(args []string) error {
+	k := 10
+	imagesFromEachSet := 5
+	recognize := false
+	opts := detection.DefaultOptions()
+	cascadePath := ""
+	var dataSets []int
+
+	if len(args) == 0 || args[0] == "-h" {
+		DetectHelp()
+		return nil
+	}
+	path := args[0]
+	args = args[1:]
+
+	for i, flag := range args {
+		switch flag {
+		case "-k":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			k = value
+		case "-i":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			imagesFromEachSet = value
+		case "-t":
+			value, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil {
+				return err
+			}
+			opts.Threshold = value
+		case "-d":
+			j := i + 1
+			for j < len(args) && !strings.HasPrefix(args[j], "-") {
+				value, err := strconv.Atoi(args[j])
+				if err != nil {
+					return err
+				}
+				dataSets = append(dataSets, value)
+				j++
+			}
+		case "-c":
+			cascadePath = args[i+1]
+		case "-r":
+			recognize = true
+		}
+	}
+
+	if len(dataSets) == 0 {
+		dataSets = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	}
+
+	img, err := image.LoadPgmImage(path)
+	if err != nil {
+		return err
+	}
+
+	var faces []image.Rect
+	if cascadePath != "" {
+		cascade, err := image.LoadHaarCascade(cascadePath)
+		if err != nil {
+			return err
+		}
+
+		faces = cascade.Detect(*img, image.HaarOptions{})
+		for i, face := range faces {
+			fmt.Printf("face %d: x=%d y=%d width=%d height=%d\n", i+1, face.X, face.Y, face.Width, face.Height)
+		}
+	} else {
+		fs, err := detection.TrainFaceSpace(dataSets, imagesFromEachSet, k, windowRows, windowCols, "./")
+		if err != nil {
+			return err
+		}
+
+		boxes, err := detection.Detect(*img, fs, opts)
+		if err != nil {
+			return err
+		}
+		for i, box := range boxes {
+			fmt.Printf("face %d: x=%d y=%d width=%d height=%d score=%.3f\n", i+1, box.X, box.Y, box.Width, box.Height, box.Score)
+			faces = append(faces, image.Rect{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height})
+		}
+	}
+
+	if len(faces) == 0 {
+		fmt.Println("no faces found")
+		return nil
+	}
+	if !recognize {
+		return nil
+	}
+
+	// the faces are scaled to the size of the data set images before recognition
+	probes, err := image.CropFaces(*img, faces, 112, 92)
+	if err != nil {
+		return err
+	}
+
+	// one model is trained for all of the faces with the same data sets, -k and -i as the face space
+	recognizer, err := r.NewRecognizer(dataSets, k, imagesFromEachSet, "./", r.Options{})
+	if err != nil {
+		return err
+	}
+	for i, probe := range probes {
+		matchIndex, similarity, err := recognizer.Match(probe)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("face", i+1, "matches: set", dataSets[(matchIndex-1)/imagesFromEachSet], "| image", (matchIndex-1)%imagesFromEachSet+1)
+		fmt.Printf("similarity: %.1f%% \n", similarity)
+	}
+	return nil
+}
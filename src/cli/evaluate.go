@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	r "face_recognition/recognition"
+)
+
+// prints usage instructions of the evaluate command
+func EvaluateHelp() {
+	fmt.Println(`
+usage:
+    ./face_recognition evaluate [options]
+
+options:
+    -h             shows this help message and terminates
+    -k <num>       sets the number of eigenfaces, kernel PCA components or 2DPCA axes. The default value is 5.
+    -d <num ...>   specify the data sets (people) used (e.g., 1 2 3). By default sets 1-10 are used.
+    -i <num>       specify how many images of each set are used for training (1-9). The default value is 5.
+    -r <method>    evaluate only one method: "eigenfaces", "kpca" or "2dpca". By default all of them are compared.
+    -g <kernel>    kernel of kpca, see the -g option of the main program.
+    -m <mask>      mask out background and hair. <mask> is "ellipse" or a path to a PGM mask file.
+    -b <backend>   how the eigenvectors are computed, see the -b option of the main program. "svd" and "rsvd" need -r eigenfaces.
+    -f             compute in single precision (float32)
+
+each method is trained once with the first -i images of every set and the remaining images of the sets are
+matched against the training images. The command prints how many of them were matched to the correct person.
+
+examples:
+    ./face_recognition evaluate                       # Compare the methods with 5 training images of sets 1-10
+    ./face_recognition evaluate -i 2 -k 3             # Compare the methods with only two training images per person
+    ./face_recognition evaluate -r kpca -g poly:3     # Evaluate kernel PCA with a cubic polynomial kernel
+	`)
+}
+
+// evaluates the recognition methods on the images that are left out of training and prints their accuracy
+func Evaluate(args []string) error {
+	k := 5
+	trainImages := 5
+	var dataSets []int
+	var opts r.Options
+	methods := []string{"eigenfaces", "kpca", "2dpca"}
+
+	for i, flag := range args {
+		switch flag {
+		case "-h":
+			EvaluateHelp()
+			return nil
+		case "-k":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			k = value
+		case "-i":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			trainImages = value
+		case "-d":
+			j := i + 1
+			for j < len(args) && !strings.HasPrefix(args[j], "-") {
+				value, err := strconv.Atoi(args[j])
+				if err != nil {
+					return err
+				}
+				dataSets = append(dataSets, value)
+				j++
+			}
+		case "-r":
+			if _, err := ParseMethod(args[i+1]); err != nil {
+				return err
+			}
+			methods = []string{args[i+1]}
+		case "-g":
+			kernel, err := ParseKernel(args[i+1])
+			if err != nil {
+				return err
+			}
+			opts.Kernel = kernel
+		case "-m":
+			mask, err := LoadMask(args[i+1])
+			if err != nil {
+				return err
+			}
+			opts.Mask = mask
+		case "-b":
+			backend, err := ParseBackend(args[i+1])
+			if err != nil {
+				return err
+			}
+			opts.Backend = backend
+		case "-f":
+			opts.Float32 = true
+		}
+	}
+
+	if len(dataSets) == 0 {
+		dataSets = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	}
+
+	fmt.Println("data sets:", dataSets, "| training images per set:", trainImages, "| k:", k)
+	for _, name := range methods {
+		// the names were validated while parsing the flags
+		method, _ := ParseMethod(name)
+		opts.Method = method
+		accuracy, err := r.Evaluate(dataSets, trainImages, k, "./", opts)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-12s accuracy: %.1f%%\n", name, 100*accuracy)
+	}
+
+	return nil
+}
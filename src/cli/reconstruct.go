@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"face_recognition/image"
+	r "face_recognition/recognition"
+)
+
+// prints usage instructions of the reconstruct command
+func ReconstructHelp() {
+	fmt.Println(`
+usage:
+    ./face_recognition reconstruct [options]
+
+options:
+    -h             shows this help message and terminates
+    -k <num>       sets the highest number of eigenfaces to use. The default value is 20.
+    -d <num ...>   specify the data sets used to build the face space (e.g., 1 2 3). By default sets 1-5 are used.
+    -i <num>       specify how many images are loaded from each set. The default value is 10.
+    -s <num num>   specify the image to reconstruct as <set image>. By default set 1 image 1 is used.
+    -o <file.pgm>  write the reconstruction with the highest number of eigenfaces to a PGM file
+
+the command prints the size of the compressed image, PSNR and SSIM for every number of eigenfaces up to -k.
+
+examples:
+    ./face_recognition reconstruct -s 20 1                 # Reconstruct an image that is not in the training data
+    ./face_recognition reconstruct -k 40 -o face.pgm       # Use up to 40 eigenfaces and save the result
+	`)
+}
+
+// reconstructs an image from the data directory with an increasing number of eigenfaces
+// and prints the compressed size and quality of each reconstruction
+func Reconstruct(args []string) error {
+	k := 20
+	imagesFromEachSet := 10
+	testImage := []int{1, 1}
+	output := ""
+	var dataSets []int
+
+	for i, flag := range args {
+		switch flag {
+		case "-h":
+			ReconstructHelp()
+			return nil
+		case "-k":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			k = value
+		case "-i":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			imagesFromEachSet = value
+		case "-d":
+			j := i + 1
+			for j < len(args) && !strings.HasPrefix(args[j], "-") {
+				value, err := strconv.Atoi(args[j])
+				if err != nil {
+					return err
+				}
+				dataSets = append(dataSets, value)
+				j++
+			}
+		case "-s":
+			set, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			num, err := strconv.Atoi(args[i+2])
+			if err != nil {
+				return err
+			}
+			testImage = []int{set, num}
+		case "-o":
+			output = args[i+1]
+		}
+	}
+
+	if len(dataSets) == 0 {
+		dataSets = []int{1, 2, 3, 4, 5}
+	}
+
+	fs, err := r.LoadFaceSpace(dataSets, imagesFromEachSet, k, "./")
+	if err != nil {
+		return err
+	}
+	img, err := image.LoadPgmImage("./data/s" + strconv.Itoa(testImage[0]) + "/" + strconv.Itoa(testImage[1]) + ".pgm")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("image: set", testImage[0], "| image", testImage[1], "| original size", len(img.Data), "bytes")
+	fmt.Printf("%4s %8s %10s %8s\n", "k", "bytes", "PSNR (dB)", "SSIM")
+	for n := 1; n <= k; n++ {
+		code, err := fs.Encode(*img, n)
+		if err != nil {
+			return err
+		}
+		decoded, err := fs.Decode(code)
+		if err != nil {
+			return err
+		}
+
+		psnr, err := image.PSNR(*img, decoded)
+		if err != nil {
+			return err
+		}
+		ssim, err := image.SSIM(*img, decoded)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%4d %8d %10.2f %8.4f\n", n, len(code), psnr, ssim)
+
+		if n == k && output != "" {
+			if err := image.SavePgmImage(output, decoded); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	r "face_recognition/recognition"
+)
+
+// prints usage instructions of the spectrum command
+func SpectrumHelp() {
+	fmt.Println(`
+usage:
+    ./face_recognition spectrum [options]
+
+options:
+    -h             shows this help message and terminates
+    -d <num ...>   specify the data sets used for training (e.g., 1 2 3). By default sets 1-5 are used.
+    -i <num>       specify how many images are loaded from each set. The default value is 10.
+    -m <mask>      mask out background and hair. <mask> is "ellipse" or a path to a PGM mask file.
+    -b <backend>   how the eigenvalues are computed: "symmetric" (default), "qr", "svd" or "jacobi", see the -b option of the main program.
+                   The spectrum is always computed in full, so "lanczos", "subspace" and "rsvd" can't be used.
+    -n <num>       print only the first <num> eigenvalues. By default all of them are printed.
+    -c             print CSV instead of a table. The variances are then fractions instead of percents.
+    -o <file.svg>  write a scree plot of the explained and cumulative variance to an SVG file
+
+the command prints every eigenvalue of the training data with the fraction of the variance it explains,
+which helps choosing -k: a good value is usually where the scree plot flattens out.
+
+examples:
+    ./face_recognition spectrum -n 20                 # Show the 20 largest eigenvalues of sets 1-5
+    ./face_recognition spectrum -d 1 2 3 -o scree.svg # Draw a scree plot of sets 1-3
+    ./face_recognition spectrum -c > spectrum.csv     # Save the spectrum as CSV
+	`)
+}
+
+// computes the eigenvalue spectrum of the training data and prints it as a table or CSV.
+// optionally writes a scree plot as SVG
+func Spectrum(args []string) error {
+	imagesFromEachSet := 10
+	rows := 0
+	asCSV := false
+	output := ""
+	var dataSets []int
+	var opts r.Options
+
+	for i, flag := range args {
+		switch flag {
+		case "-h":
+			SpectrumHelp()
+			return nil
+		case "-i":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			imagesFromEachSet = value
+		case "-d":
+			j := i + 1
+			for j < len(args) && !strings.HasPrefix(args[j], "-") {
+				value, err := strconv.Atoi(args[j])
+				if err != nil {
+					return err
+				}
+				dataSets = append(dataSets, value)
+				j++
+			}
+		case "-m":
+			mask, err := LoadMask(args[i+1])
+			if err != nil {
+				return err
+			}
+			opts.Mask = mask
+		case "-b":
+			backend, err := ParseBackend(args[i+1])
+			if err != nil {
+				return err
+			}
+			opts.Backend = backend
+		case "-n":
+			value, err := strconv.Atoi(args[i+1])
+			if err != nil {
+				return err
+			}
+			rows = value
+		case "-c":
+			asCSV = true
+		case "-o":
+			output = args[i+1]
+		}
+	}
+
+	if len(dataSets) == 0 {
+		dataSets = []int{1, 2, 3, 4, 5}
+	}
+
+	spectrum, err := r.ComputeSpectrum(dataSets, imagesFromEachSet, "./", opts)
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		if err := spectrum.WriteScreePlot(file); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+	}
+
+	shown := spectrum
+	if rows > 0 && rows < len(spectrum.Eigenvalues) {
+		shown = r.Spectrum{
+			Eigenvalues: spectrum.Eigenvalues[:rows],
+			Explained:   spectrum.Explained[:rows],
+			Cumulative:  spectrum.Cumulative[:rows],
+		}
+	}
+	if asCSV {
+		return shown.WriteCSV(os.Stdout)
+	}
+
+	fmt.Println("data sets:", dataSets, "| eigenvalues:", len(spectrum.Eigenvalues))
+	if err := shown.WriteTable(os.Stdout); err != nil {
+		return err
+	}
+	for _, fraction := range []float64{0.8, 0.9, 0.95, 0.99} {
+		fmt.Printf("%.0f%% of the variance is explained by %d eigenfaces\n", 100*fraction, spectrum.ComponentsFor(fraction))
+	}
+
+	return nil
+}
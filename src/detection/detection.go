@@ -0,0 +1,202 @@
+package detection
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+	r "face_recognition/recognition"
+)
+
+// define possible errors
+var (
+	errInvalidOptions = fmt.Errorf("invalid detection options")
+	errImageTooSmall  = fmt.Errorf("image is smaller than the detection window")
+)
+
+// Box is a detected face in the coordinates of the original image
+// Score is the distance from face space of the window, lower is more face-like
+type Box struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Score  float64
+}
+
+// settings of the sliding window detector
+type Options struct {
+	Stride    int     // step between windows in pixels of each pyramid level
+	ScaleStep float64 // size ratio between consecutive pyramid levels, must be over 1
+	Threshold float64 // windows with a higher per-pixel DFFS are rejected
+	Overlap   float64 // boxes overlapping a better box more than this (IoU) are suppressed
+}
+
+// returns the detection settings used by the detect command
+func DefaultOptions() Options {
+	return Options{
+		Stride:    2,
+		ScaleStep: 1.25,
+		Threshold: 0.65,
+		Overlap:   0.3,
+	}
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads count images from each of the data sets, scales them to the window size and
+// builds a face space with k eigenfaces for detection
+// returns the face space or an error
+func TrainFaceSpace(dataSets []int, count, k, windowRows, windowCols int, rootDir string) (r.FaceSpace, error) {
+	var windows []m.Matrix
+
+	for _, set := range dataSets {
+		for i := range count {
+			face, err := image.LoadPgmImage(rootDir + "data/s" + strconv.Itoa(set) + "/" + strconv.Itoa(i+1) + ".pgm")
+			if err != nil {
+				return r.FaceSpace{}, err
+			}
+
+			window, err := image.Resize(*face, windowRows, windowCols)
+			if err != nil {
+				return r.FaceSpace{}, err
+			}
+			if !normalizeWindow(window) {
+				continue
+			}
+			windows = append(windows, window)
+		}
+	}
+
+	return r.NewFaceSpace(windows, k)
+}
+
+// scans the grayscale image with a window of the face space size over every position and
+// an image pyramid. Each window is normalized to zero mean and unit variance and scored by
+// its per-pixel distance from face space. Overlapping detections are removed with non-maximum suppression
+// returns the detected boxes ordered from the best score or an error
+func Detect(img m.Matrix, fs r.FaceSpace, opts Options) ([]Box, error) {
+	if opts.Stride < 1 || opts.ScaleStep <= 1 {
+		return nil, errInvalidOptions
+	}
+	if img.Rows < fs.Rows || img.Cols < fs.Cols {
+		return nil, errImageTooSmall
+	}
+
+	var boxes []Box
+	pixels := math.Sqrt(float64(fs.Rows * fs.Cols))
+
+	for scale := 1.0; ; scale *= opts.ScaleStep {
+		rows := int(math.Round(float64(img.Rows) / scale))
+		cols := int(math.Round(float64(img.Cols) / scale))
+		if rows < fs.Rows || cols < fs.Cols {
+			break
+		}
+
+		level := img
+		if scale != 1 {
+			var err error
+			level, err = image.Resize(img, rows, cols)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for y := 0; y+fs.Rows <= rows; y += opts.Stride {
+			for x := 0; x+fs.Cols <= cols; x += opts.Stride {
+				window, err := image.Crop(level, x, y, fs.Cols, fs.Rows)
+				if err != nil {
+					return nil, err
+				}
+				if !normalizeWindow(window) {
+					continue
+				}
+
+				distance, err := fs.DFFS(window)
+				if err != nil {
+					return nil, err
+				}
+
+				score := distance / pixels
+				if score > opts.Threshold {
+					continue
+				}
+				boxes = append(boxes, Box{
+					X:      int(math.Round(float64(x) * scale)),
+					Y:      int(math.Round(float64(y) * scale)),
+					Width:  int(math.Round(float64(fs.Cols) * scale)),
+					Height: int(math.Round(float64(fs.Rows) * scale)),
+					Score:  score,
+				})
+			}
+		}
+	}
+
+	return NonMaxSuppression(boxes, opts.Overlap), nil
+}
+
+// keeps the best scoring boxes and removes every box that overlaps an already kept box
+// more than the given intersection over union
+// returns the kept boxes ordered from the best score
+func NonMaxSuppression(boxes []Box, overlap float64) []Box {
+	sorted := make([]Box, len(boxes))
+	copy(sorted, boxes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Score < sorted[j].Score
+	})
+
+	var result []Box
+	for _, box := range sorted {
+		suppressed := false
+		for _, kept := range result {
+			if IntersectionOverUnion(box, kept) > overlap {
+				suppressed = true
+				break
+			}
+		}
+		if !suppressed {
+			result = append(result, box)
+		}
+	}
+
+	return result
+}
+
+// computes the area of the intersection of two boxes divided by the area of their union
+func IntersectionOverUnion(a, b Box) float64 {
+	width := min(a.X+a.Width, b.X+b.Width) - max(a.X, b.X)
+	height := min(a.Y+a.Height, b.Y+b.Height) - max(a.Y, b.Y)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+
+	intersection := float64(width * height)
+	union := float64(a.Width*a.Height+b.Width*b.Height) - intersection
+	return intersection / union
+}
+
+// normalizes the window in place to zero mean and unit variance
+// returns false if the window is flat and can't be normalized
+func normalizeWindow(window m.Matrix) bool {
+	mean := 0.0
+	for _, val := range window.Data {
+		mean += val
+	}
+	mean /= float64(len(window.Data))
+
+	variance := 0.0
+	for _, val := range window.Data {
+		variance += (val - mean) * (val - mean)
+	}
+	std := math.Sqrt(variance / float64(len(window.Data)))
+	if std < 1e-9 {
+		return false
+	}
+
+	for i, val := range window.Data {
+		window.Data[i] = (val - mean) / std
+	}
+	return true
+}
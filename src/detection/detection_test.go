@@ -0,0 +1,181 @@
+package detection
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+)
+
+const EPSILON = 1e-6
+
+// places a face scaled to height * width pixels on a noisy background at (x, y)
+func createScene(t *testing.T, rows, cols, x, y, height, width int) m.Matrix {
+	face, err := image.LoadPgmImage("../data/s20/1.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scaled, err := image.Resize(*face, height, width)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	scene := m.Matrix{
+		Rows: rows,
+		Cols: cols,
+		Data: make([]float64, rows*cols),
+	}
+	for i := range scene.Data {
+		scene.Data[i] = 100 + rng.Float64()*60
+	}
+	for row := range height {
+		copy(scene.Data[(y+row)*cols+x:(y+row)*cols+x+width], scaled.Data[row*width:(row+1)*width])
+	}
+
+	return scene
+}
+
+func TestDetect(t *testing.T) {
+	fs, err := TrainFaceSpace([]int{1, 2, 3, 4, 5, 6, 7, 8}, 5, 10, 28, 23, "../")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		scene   m.Matrix
+		want    Box
+		opts    Options
+		wantErr error
+	}{
+		{
+			name:    "face at window size is found",
+			scene:   createScene(t, 60, 70, 30, 20, 28, 23),
+			want:    Box{X: 30, Y: 20, Width: 23, Height: 28},
+			opts:    DefaultOptions(),
+			wantErr: nil,
+		},
+		{
+			name:    "larger face is found from the pyramid",
+			scene:   createScene(t, 90, 90, 20, 10, 56, 46),
+			want:    Box{X: 20, Y: 10, Width: 46, Height: 56},
+			opts:    DefaultOptions(),
+			wantErr: nil,
+		},
+		{
+			name:    "image smaller than the window fails",
+			scene:   createScene(t, 28, 20, 0, 0, 28, 20),
+			want:    Box{},
+			opts:    DefaultOptions(),
+			wantErr: errImageTooSmall,
+		},
+		{
+			name:    "invalid scale step fails",
+			scene:   createScene(t, 60, 70, 30, 20, 28, 23),
+			want:    Box{},
+			opts:    Options{Stride: 1, ScaleStep: 1},
+			wantErr: errInvalidOptions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boxes, err := Detect(tt.scene, fs, tt.opts)
+			if err != tt.wantErr {
+				t.Fatalf("Detect(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(boxes) == 0 {
+				t.Fatalf("Detect(): didn't find any faces")
+			}
+			if iou := IntersectionOverUnion(boxes[0], tt.want); iou < 0.5 {
+				t.Errorf("Detect(): best box %+v overlaps the face only by %f", boxes[0], iou)
+			}
+		})
+	}
+}
+
+func TestNonMaxSuppression(t *testing.T) {
+	tests := []struct {
+		name    string
+		boxes   []Box
+		overlap float64
+		want    []Box
+	}{
+		{
+			name: "overlapping worse box is removed",
+			boxes: []Box{
+				{X: 1, Y: 0, Width: 10, Height: 10, Score: 0.5},
+				{X: 0, Y: 0, Width: 10, Height: 10, Score: 0.2},
+				{X: 30, Y: 30, Width: 10, Height: 10, Score: 0.4},
+			},
+			overlap: 0.3,
+			want: []Box{
+				{X: 0, Y: 0, Width: 10, Height: 10, Score: 0.2},
+				{X: 30, Y: 30, Width: 10, Height: 10, Score: 0.4},
+			},
+		},
+		{
+			name:    "no boxes returns nothing",
+			boxes:   nil,
+			overlap: 0.3,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NonMaxSuppression(tt.boxes, tt.overlap)
+			if len(result) != len(tt.want) {
+				t.Fatalf("NonMaxSuppression(): returned %d boxes, want %d", len(result), len(tt.want))
+			}
+			for i := range result {
+				if result[i] != tt.want[i] {
+					t.Errorf("NonMaxSuppression(): at index %d, got %+v, want %+v", i, result[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIntersectionOverUnion(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Box
+		b    Box
+		want float64
+	}{
+		{
+			name: "same box",
+			a:    Box{X: 0, Y: 0, Width: 4, Height: 4},
+			b:    Box{X: 0, Y: 0, Width: 4, Height: 4},
+			want: 1,
+		},
+		{
+			name: "half overlap",
+			a:    Box{X: 0, Y: 0, Width: 4, Height: 4},
+			b:    Box{X: 2, Y: 0, Width: 4, Height: 4},
+			want: 8.0 / 24.0,
+		},
+		{
+			name: "separate boxes",
+			a:    Box{X: 0, Y: 0, Width: 4, Height: 4},
+			b:    Box{X: 10, Y: 10, Width: 4, Height: 4},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IntersectionOverUnion(tt.a, tt.b)
+			if math.Abs(result-tt.want) > EPSILON {
+				t.Errorf("IntersectionOverUnion(): got %f, want %f", result, tt.want)
+			}
+		})
+	}
+}
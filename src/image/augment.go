@@ -0,0 +1,200 @@
+package image
+
+import (
+	"math"
+	"math/rand"
+
+	m "face_recognition/matrix"
+)
+
+// settings for training-time data augmentation. Every enabled transformation
+// produces one extra copy of each training image
+type Augmentation struct {
+	Mirror     bool      // horizontally mirrored copy
+	Rotations  []float64 // rotation angles in degrees
+	Shifts     [][2]int  // translations as {dx, dy} pixel pairs
+	Jitter     int       // number of copies with random brightness and contrast
+	Brightness float64   // maximum brightness change of a jittered copy
+	Contrast   float64   // maximum relative contrast change of a jittered copy
+	Noise      float64   // standard deviation of gaussian noise added to jittered copies
+	Seed       int64     // seed for the random jitter and noise
+}
+
+// returns the augmentation settings used by the command-line options
+func DefaultAugmentation(seed int64) Augmentation {
+	return Augmentation{
+		Mirror:     true,
+		Rotations:  []float64{-5, 5},
+		Shifts:     [][2]int{{-2, 0}, {2, 0}},
+		Jitter:     2,
+		Brightness: 20,
+		Contrast:   0.2,
+		Noise:      4,
+		Seed:       seed,
+	}
+}
+
+// returns the number of extra images generated from each training image
+func (aug Augmentation) Count() int {
+	count := len(aug.Rotations) + len(aug.Shifts) + aug.Jitter
+	if aug.Mirror {
+		count++
+	}
+	return count
+}
+
+// generates augmented copies of a 2D image. The original image is not included
+// the rng is shared between calls so that a whole training set is reproducible from one seed
+// returns a slice of new image matrices
+func Augment(image m.Matrix, aug Augmentation, rng *rand.Rand) []m.Matrix {
+	result := make([]m.Matrix, 0, aug.Count())
+
+	if aug.Mirror {
+		result = append(result, MirrorHorizontal(image))
+	}
+	for _, angle := range aug.Rotations {
+		result = append(result, Rotate(image, angle))
+	}
+	for _, shift := range aug.Shifts {
+		result = append(result, Translate(image, shift[0], shift[1]))
+	}
+	for range aug.Jitter {
+		brightness := (rng.Float64()*2 - 1) * aug.Brightness
+		contrast := 1 + (rng.Float64()*2-1)*aug.Contrast
+		jittered := AdjustBrightnessContrast(image, brightness, contrast)
+		if aug.Noise > 0 {
+			jittered = AddGaussianNoise(jittered, aug.Noise, rng)
+		}
+		result = append(result, jittered)
+	}
+
+	return result
+}
+
+// mirrors the image horizontally
+// returns a new matrix containing the result
+func MirrorHorizontal(image m.Matrix) m.Matrix {
+	result := m.Matrix{
+		Rows: image.Rows,
+		Cols: image.Cols,
+		Data: make([]float64, len(image.Data)),
+	}
+
+	for y := range image.Rows {
+		for x := range image.Cols {
+			result.Data[y*image.Cols+x] = image.Data[y*image.Cols+image.Cols-1-x]
+		}
+	}
+
+	return result
+}
+
+// rotates the image clockwise around its center by the given angle in degrees using bilinear interpolation
+// pixels rotated in from outside the image take the value of the nearest edge pixel
+// returns a new matrix containing the result
+func Rotate(image m.Matrix, degrees float64) m.Matrix {
+	result := m.Matrix{
+		Rows: image.Rows,
+		Cols: image.Cols,
+		Data: make([]float64, len(image.Data)),
+	}
+
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	centerX := float64(image.Cols-1) / 2
+	centerY := float64(image.Rows-1) / 2
+
+	for y := range image.Rows {
+		dy := float64(y) - centerY
+		for x := range image.Cols {
+			dx := float64(x) - centerX
+			// inverse mapping from the result pixel back to the source image
+			srcX := cos*dx + sin*dy + centerX
+			srcY := -sin*dx + cos*dy + centerY
+			result.Data[y*image.Cols+x] = bilinear(image, srcX, srcY)
+		}
+	}
+
+	return result
+}
+
+// moves the image by dx pixels right and dy pixels down
+// pixels moved in from outside the image take the value of the nearest edge pixel
+// returns a new matrix containing the result
+func Translate(image m.Matrix, dx, dy int) m.Matrix {
+	result := m.Matrix{
+		Rows: image.Rows,
+		Cols: image.Cols,
+		Data: make([]float64, len(image.Data)),
+	}
+
+	for y := range image.Rows {
+		srcY := clampInt(y-dy, 0, image.Rows-1)
+		for x := range image.Cols {
+			srcX := clampInt(x-dx, 0, image.Cols-1)
+			result.Data[y*image.Cols+x] = image.Data[srcY*image.Cols+srcX]
+		}
+	}
+
+	return result
+}
+
+// scales the contrast around mid-gray by contrast and adds brightness to every pixel
+// values are clamped to the 0-255 range of the PGM images
+// returns a new matrix containing the result
+func AdjustBrightnessContrast(image m.Matrix, brightness, contrast float64) m.Matrix {
+	result := m.Matrix{
+		Rows: image.Rows,
+		Cols: image.Cols,
+		Data: make([]float64, len(image.Data)),
+	}
+
+	for i, val := range image.Data {
+		result.Data[i] = clampPixel((val-128)*contrast + 128 + brightness)
+	}
+
+	return result
+}
+
+// adds gaussian noise with the given standard deviation to every pixel
+// values are clamped to the 0-255 range of the PGM images
+// returns a new matrix containing the result
+func AddGaussianNoise(image m.Matrix, sigma float64, rng *rand.Rand) m.Matrix {
+	result := m.Matrix{
+		Rows: image.Rows,
+		Cols: image.Cols,
+		Data: make([]float64, len(image.Data)),
+	}
+
+	for i, val := range image.Data {
+		result.Data[i] = clampPixel(val + rng.NormFloat64()*sigma)
+	}
+
+	return result
+}
+
+// samples the image at a non-integer position using bilinear interpolation
+// positions outside of the image are clamped to the nearest edge
+func bilinear(image m.Matrix, x, y float64) float64 {
+	x = math.Max(0, math.Min(x, float64(image.Cols-1)))
+	y = math.Max(0, math.Min(y, float64(image.Rows-1)))
+
+	x0 := int(x)
+	y0 := int(y)
+	x1 := min(x0+1, image.Cols-1)
+	y1 := min(y0+1, image.Rows-1)
+	fx := x - float64(x0)
+	fy := y - float64(y0)
+
+	top := image.Data[y0*image.Cols+x0]*(1-fx) + image.Data[y0*image.Cols+x1]*fx
+	bottom := image.Data[y1*image.Cols+x0]*(1-fx) + image.Data[y1*image.Cols+x1]*fx
+
+	return top*(1-fy) + bottom*fy
+}
+
+func clampInt(val, low, high int) int {
+	return max(low, min(val, high))
+}
+
+func clampPixel(val float64) float64 {
+	return math.Max(0, math.Min(val, 255))
+}
@@ -0,0 +1,225 @@
+package image
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestMirrorHorizontal(t *testing.T) {
+	tests := []struct {
+		name  string
+		image m.Matrix
+		want  m.Matrix
+	}{
+		{
+			name: "columns are reversed",
+			image: m.Matrix{
+				Rows: 2,
+				Cols: 3,
+				Data: []float64{1, 2, 3, 4, 5, 6},
+			},
+			want: m.Matrix{
+				Rows: 2,
+				Cols: 3,
+				Data: []float64{3, 2, 1, 6, 5, 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MirrorHorizontal(tt.image)
+			for i := range tt.want.Data {
+				if result.Data[i] != tt.want.Data[i] {
+					t.Errorf("MirrorHorizontal(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	image := m.Matrix{
+		Rows: 3,
+		Cols: 3,
+		Data: []float64{
+			1, 2, 3,
+			4, 5, 6,
+			7, 8, 9,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		degrees float64
+		want    m.Matrix
+	}{
+		{
+			name:    "zero angle keeps the image",
+			degrees: 0,
+			want:    image,
+		},
+		{
+			name:    "90 degrees rotates clockwise",
+			degrees: 90,
+			want: m.Matrix{
+				Rows: 3,
+				Cols: 3,
+				Data: []float64{
+					7, 4, 1,
+					8, 5, 2,
+					9, 6, 3,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Rotate(image, tt.degrees)
+			for i := range tt.want.Data {
+				if math.Abs(result.Data[i]-tt.want.Data[i]) > EPSILON {
+					t.Errorf("Rotate(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	image := m.Matrix{
+		Rows: 2,
+		Cols: 3,
+		Data: []float64{1, 2, 3, 4, 5, 6},
+	}
+
+	tests := []struct {
+		name string
+		dx   int
+		dy   int
+		want m.Matrix
+	}{
+		{
+			name: "moving right repeats the left edge",
+			dx:   1,
+			dy:   0,
+			want: m.Matrix{
+				Rows: 2,
+				Cols: 3,
+				Data: []float64{1, 1, 2, 4, 4, 5},
+			},
+		},
+		{
+			name: "moving up repeats the bottom edge",
+			dx:   0,
+			dy:   -1,
+			want: m.Matrix{
+				Rows: 2,
+				Cols: 3,
+				Data: []float64{4, 5, 6, 4, 5, 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Translate(image, tt.dx, tt.dy)
+			for i := range tt.want.Data {
+				if result.Data[i] != tt.want.Data[i] {
+					t.Errorf("Translate(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAdjustBrightnessContrast(t *testing.T) {
+	image := m.Matrix{
+		Rows: 1,
+		Cols: 4,
+		Data: []float64{0, 100, 128, 250},
+	}
+
+	tests := []struct {
+		name       string
+		brightness float64
+		contrast   float64
+		want       []float64
+	}{
+		{
+			name:       "brightness is added and clamped",
+			brightness: 10,
+			contrast:   1,
+			want:       []float64{10, 110, 138, 255},
+		},
+		{
+			name:       "contrast scales around mid-gray",
+			brightness: 0,
+			contrast:   0.5,
+			want:       []float64{64, 114, 128, 189},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := AdjustBrightnessContrast(image, tt.brightness, tt.contrast)
+			for i := range tt.want {
+				if math.Abs(result.Data[i]-tt.want[i]) > EPSILON {
+					t.Errorf("AdjustBrightnessContrast(): at index %d, got %f, want %f", i, result.Data[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAugment(t *testing.T) {
+	image := m.Matrix{
+		Rows: 4,
+		Cols: 4,
+		Data: []float64{
+			10, 20, 30, 40,
+			50, 60, 70, 80,
+			90, 100, 110, 120,
+			130, 140, 150, 160,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		aug       Augmentation
+		wantCount int
+	}{
+		{
+			name:      "no augmentation returns nothing",
+			aug:       Augmentation{},
+			wantCount: 0,
+		},
+		{
+			name:      "default augmentation returns one copy per transformation",
+			aug:       DefaultAugmentation(1),
+			wantCount: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := Augment(image, tt.aug, rand.New(rand.NewSource(tt.aug.Seed)))
+			second := Augment(image, tt.aug, rand.New(rand.NewSource(tt.aug.Seed)))
+			if len(first) != tt.wantCount || tt.aug.Count() != tt.wantCount {
+				t.Fatalf("Augment(): returned %d images, want %d", len(first), tt.wantCount)
+			}
+
+			// the same seed must give the same images
+			for i := range first {
+				for j := range first[i].Data {
+					if first[i].Data[j] != second[i].Data[j] {
+						t.Errorf("Augment(): image %d at index %d differs between runs with the same seed", i, j)
+					}
+				}
+			}
+		})
+	}
+}
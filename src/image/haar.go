@@ -0,0 +1,450 @@
+package image
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errCascadeReading     = fmt.Errorf("error reading cascade file")
+	errUnsupportedCascade = fmt.Errorf("unsupported cascade: only untilted HAAR cascades are supported")
+)
+
+// Rect is an axis aligned rectangle in image coordinates
+type Rect struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// HaarCascade is a boosted cascade of Haar-like features in the format used by OpenCV
+// the features are defined for a Width * Height window
+type HaarCascade struct {
+	Width    int
+	Height   int
+	Stages   []haarStage
+	Features []haarFeature
+}
+
+// settings of the cascade detector. The zero value of a field means the OpenCV default
+type HaarOptions struct {
+	ScaleFactor  float64 // size ratio between consecutive scales, over 1. Default 1.1
+	MinNeighbors int     // detections a face needs to be kept after grouping. Default 3, negative skips grouping
+	MinSize      int     // smallest face width in pixels. Default is the window width
+}
+
+type haarStage struct {
+	threshold float64
+	weak      []haarTree
+}
+
+type haarTree struct {
+	nodes  []haarNode
+	leaves []float64
+}
+
+// a split of a weak classifier. left and right are indices of the next node
+// or when they are zero or negative the negated index of a leaf value
+type haarNode struct {
+	left      int
+	right     int
+	feature   int
+	threshold float64
+}
+
+type haarFeature struct {
+	rects   []Rect
+	weights []float64
+}
+
+// layout of the OpenCV cascade XML file. List items are stored in elements named "_"
+type xmlCascade struct {
+	Cascade struct {
+		FeatureType string `xml:"featureType"`
+		Width       int    `xml:"width"`
+		Height      int    `xml:"height"`
+		Stages      []struct {
+			Threshold float64 `xml:"stageThreshold"`
+			Weak      []struct {
+				Nodes  string `xml:"internalNodes"`
+				Leaves string `xml:"leafValues"`
+			} `xml:"weakClassifiers>_"`
+		} `xml:"stages>_"`
+		Features []struct {
+			Rects  []string `xml:"rects>_"`
+			Tilted int      `xml:"tilted"`
+		} `xml:"features>_"`
+	} `xml:"cascade"`
+}
+
+// loads a Haar cascade from an OpenCV cascade XML file such as haarcascade_frontalface_default.xml
+// returns the cascade or an error if the file can't be read or uses unsupported features
+func LoadHaarCascade(filepath string) (*HaarCascade, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, errFileOpening
+	}
+
+	var raw xmlCascade
+	if err := xml.Unmarshal(data, &raw); err != nil {
+		return nil, errCascadeReading
+	}
+	if raw.Cascade.FeatureType != "HAAR" {
+		return nil, errUnsupportedCascade
+	}
+	if raw.Cascade.Width < 3 || raw.Cascade.Height < 3 || len(raw.Cascade.Stages) == 0 {
+		return nil, errCascadeReading
+	}
+
+	cascade := &HaarCascade{
+		Width:  raw.Cascade.Width,
+		Height: raw.Cascade.Height,
+	}
+
+	for _, feature := range raw.Cascade.Features {
+		if feature.Tilted != 0 {
+			return nil, errUnsupportedCascade
+		}
+
+		var parsed haarFeature
+		for _, rect := range feature.Rects {
+			values, err := parseFloats(rect)
+			if err != nil || len(values) != 5 {
+				return nil, errCascadeReading
+			}
+			r := Rect{
+				X:      int(values[0]),
+				Y:      int(values[1]),
+				Width:  int(values[2]),
+				Height: int(values[3]),
+			}
+			if r.X < 0 || r.Y < 0 || r.Width < 0 || r.Height < 0 ||
+				r.X+r.Width > cascade.Width || r.Y+r.Height > cascade.Height {
+				return nil, errCascadeReading
+			}
+			parsed.rects = append(parsed.rects, r)
+			parsed.weights = append(parsed.weights, values[4])
+		}
+		cascade.Features = append(cascade.Features, parsed)
+	}
+
+	for _, stage := range raw.Cascade.Stages {
+		parsed := haarStage{threshold: stage.Threshold}
+		for _, weak := range stage.Weak {
+			nodes, err := parseFloats(weak.Nodes)
+			if err != nil || len(nodes) == 0 || len(nodes)%4 != 0 {
+				return nil, errCascadeReading
+			}
+			leaves, err := parseFloats(weak.Leaves)
+			if err != nil || len(leaves) == 0 {
+				return nil, errCascadeReading
+			}
+
+			tree := haarTree{leaves: leaves}
+			for i := 0; i < len(nodes); i += 4 {
+				node := haarNode{
+					left:      int(nodes[i]),
+					right:     int(nodes[i+1]),
+					feature:   int(nodes[i+2]),
+					threshold: nodes[i+3],
+				}
+				if node.feature < 0 || node.feature >= len(cascade.Features) ||
+					-node.left >= len(leaves) || -node.right >= len(leaves) ||
+					node.left >= len(nodes)/4 || node.right >= len(nodes)/4 {
+					return nil, errCascadeReading
+				}
+				tree.nodes = append(tree.nodes, node)
+			}
+			parsed.weak = append(parsed.weak, tree)
+		}
+		cascade.Stages = append(cascade.Stages, parsed)
+	}
+
+	return cascade, nil
+}
+
+// computes the integral image of a 2D image. The result has one extra row and column of zeros
+// at the top and left so that the sum of any rectangle needs only four lookups
+// returns a new (Rows+1) x (Cols+1) matrix
+func IntegralImage(image m.Matrix) m.Matrix {
+	return integral(image, false)
+}
+
+// computes the integral image of the squared pixel values, see IntegralImage
+func SquaredIntegralImage(image m.Matrix) m.Matrix {
+	return integral(image, true)
+}
+
+func integral(image m.Matrix, squared bool) m.Matrix {
+	cols := image.Cols + 1
+	result := m.Matrix{
+		Rows: image.Rows + 1,
+		Cols: cols,
+		Data: make([]float64, (image.Rows+1)*cols),
+	}
+
+	for y := range image.Rows {
+		rowSum := 0.0
+		for x := range image.Cols {
+			val := image.Data[y*image.Cols+x]
+			if squared {
+				val *= val
+			}
+			rowSum += val
+			result.Data[(y+1)*cols+x+1] = result.Data[y*cols+x+1] + rowSum
+		}
+	}
+
+	return result
+}
+
+// returns the sum of pixels inside the rectangle using an integral image
+func RectSum(integral m.Matrix, rect Rect) float64 {
+	cols := integral.Cols
+	x0, y0 := rect.X, rect.Y
+	x1, y1 := rect.X+rect.Width, rect.Y+rect.Height
+	return integral.Data[y1*cols+x1] - integral.Data[y0*cols+x1] - integral.Data[y1*cols+x0] + integral.Data[y0*cols+x0]
+}
+
+// finds faces in a grayscale image. The image is scaled down step by step and the cascade
+// is evaluated in every window position of every scale. Overlapping detections are grouped
+// like OpenCV's groupRectangles
+// returns the face rectangles in the coordinates of the original image
+func (cascade *HaarCascade) Detect(image m.Matrix, opts HaarOptions) []Rect {
+	if opts.ScaleFactor <= 1 {
+		opts.ScaleFactor = 1.1
+	}
+	if opts.MinNeighbors == 0 {
+		opts.MinNeighbors = 3
+	}
+
+	var candidates []Rect
+	for scale := 1.0; ; scale *= opts.ScaleFactor {
+		width := int(math.Round(float64(cascade.Width) * scale))
+		height := int(math.Round(float64(cascade.Height) * scale))
+		if width > image.Cols || height > image.Rows {
+			break
+		}
+		if width < opts.MinSize {
+			continue
+		}
+
+		level := image
+		if scale != 1 {
+			// the size can't be invalid since the window fits inside the scaled image
+			level, _ = Resize(image, int(float64(image.Rows)/scale), int(float64(image.Cols)/scale))
+		}
+		sum := IntegralImage(level)
+		sqsum := SquaredIntegralImage(level)
+
+		step := 2
+		if scale > 2 {
+			step = 1
+		}
+		for y := 0; y+cascade.Height <= level.Rows; y += step {
+			for x := 0; x+cascade.Width <= level.Cols; x += step {
+				if cascade.evaluate(sum, sqsum, x, y) {
+					candidates = append(candidates, Rect{
+						X:      int(math.Round(float64(x) * scale)),
+						Y:      int(math.Round(float64(y) * scale)),
+						Width:  width,
+						Height: height,
+					})
+				}
+			}
+		}
+	}
+
+	if opts.MinNeighbors < 0 {
+		return candidates
+	}
+	return GroupRectangles(candidates, opts.MinNeighbors, 0.2)
+}
+
+// runs the cascade on the window with the top left corner at (x, y)
+// returns true if the window passes every stage
+func (cascade *HaarCascade) evaluate(sum, sqsum m.Matrix, x, y int) bool {
+	// features are normalized by the standard deviation of the window without its 1 pixel border
+	norm := Rect{X: x + 1, Y: y + 1, Width: cascade.Width - 2, Height: cascade.Height - 2}
+	area := float64(norm.Width * norm.Height)
+	windowSum := RectSum(sum, norm)
+	factor := area*RectSum(sqsum, norm) - windowSum*windowSum
+	if factor > 0 {
+		factor = math.Sqrt(factor)
+	} else {
+		factor = 1
+	}
+
+	for _, stage := range cascade.Stages {
+		stageSum := 0.0
+		for _, tree := range stage.weak {
+			idx := 0
+			for {
+				node := tree.nodes[idx]
+				feature := cascade.Features[node.feature]
+				value := 0.0
+				for i, rect := range feature.rects {
+					rect.X += x
+					rect.Y += y
+					value += feature.weights[i] * RectSum(sum, rect)
+				}
+
+				next := node.right
+				if value/factor < node.threshold {
+					next = node.left
+				}
+				if next <= 0 {
+					stageSum += tree.leaves[-next]
+					break
+				}
+				idx = next
+			}
+		}
+		if stageSum < stage.threshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clusters similar rectangles and averages every cluster. Clusters with minNeighbors or fewer
+// rectangles are dropped, as are clusters inside a stronger cluster. eps is the relative size
+// difference allowed between rectangles of the same cluster
+// returns the averaged rectangles
+func GroupRectangles(rects []Rect, minNeighbors int, eps float64) []Rect {
+	// union-find over rectangles that are similar to each other
+	parent := make([]int, len(rects))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range rects {
+		for j := range i {
+			if similarRects(rects[i], rects[j], eps) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	var labels []int
+	sums := map[int]*[4]int{}
+	counts := map[int]int{}
+	for i, rect := range rects {
+		label := find(i)
+		if _, ok := sums[label]; !ok {
+			sums[label] = &[4]int{}
+			labels = append(labels, label)
+		}
+		sums[label][0] += rect.X
+		sums[label][1] += rect.Y
+		sums[label][2] += rect.Width
+		sums[label][3] += rect.Height
+		counts[label]++
+	}
+
+	averaged := make([]Rect, len(labels))
+	weights := make([]int, len(labels))
+	for i, label := range labels {
+		n := float64(counts[label])
+		averaged[i] = Rect{
+			X:      int(math.Round(float64(sums[label][0]) / n)),
+			Y:      int(math.Round(float64(sums[label][1]) / n)),
+			Width:  int(math.Round(float64(sums[label][2]) / n)),
+			Height: int(math.Round(float64(sums[label][3]) / n)),
+		}
+		weights[i] = counts[label]
+	}
+
+	var result []Rect
+	for i, r1 := range averaged {
+		n1 := weights[i]
+		if n1 <= minNeighbors {
+			continue
+		}
+
+		inside := false
+		for j, r2 := range averaged {
+			n2 := weights[j]
+			if j == i || n2 <= minNeighbors {
+				continue
+			}
+			dx := int(math.Round(float64(r2.Width) * eps))
+			dy := int(math.Round(float64(r2.Height) * eps))
+			if r1.X >= r2.X-dx && r1.Y >= r2.Y-dy &&
+				r1.X+r1.Width <= r2.X+r2.Width+dx && r1.Y+r1.Height <= r2.Y+r2.Height+dy &&
+				(n2 > max(3, n1) || n1 < 3) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			result = append(result, r1)
+		}
+	}
+
+	return result
+}
+
+// crops every rectangle out of the image and resizes the crops to rows * cols pixels
+// so that they can be passed to the recognizer. Rectangles are clipped to the image
+// returns the crops or an error
+func CropFaces(image m.Matrix, rects []Rect, rows, cols int) ([]m.Matrix, error) {
+	faces := make([]m.Matrix, 0, len(rects))
+	for _, rect := range rects {
+		x := max(rect.X, 0)
+		y := max(rect.Y, 0)
+		width := min(rect.X+rect.Width, image.Cols) - x
+		height := min(rect.Y+rect.Height, image.Rows) - y
+
+		crop, err := Crop(image, x, y, width, height)
+		if err != nil {
+			return nil, err
+		}
+		face, err := Resize(crop, rows, cols)
+		if err != nil {
+			return nil, err
+		}
+		faces = append(faces, face)
+	}
+
+	return faces, nil
+}
+
+// checks if two rectangles belong to the same cluster
+func similarRects(r1, r2 Rect, eps float64) bool {
+	delta := eps * float64(min(r1.Width, r2.Width)+min(r1.Height, r2.Height)) * 0.5
+	return math.Abs(float64(r1.X-r2.X)) <= delta &&
+		math.Abs(float64(r1.Y-r2.Y)) <= delta &&
+		math.Abs(float64(r1.X+r1.Width-r2.X-r2.Width)) <= delta &&
+		math.Abs(float64(r1.Y+r1.Height-r2.Y-r2.Height)) <= delta
+}
+
+// parses whitespace separated numbers
+func parseFloats(text string) ([]float64, error) {
+	fields := strings.Fields(text)
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
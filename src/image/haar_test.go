@@ -0,0 +1,232 @@
+package image
+
+import (
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+// creates a rows * cols image with value 100 and a 6x6 pattern with a dark top half and
+// a bright bottom half at (x, y) matching testdata/cascade.xml
+func createPatternImage(rows, cols, x, y, size int) m.Matrix {
+	image := m.Matrix{
+		Rows: rows,
+		Cols: cols,
+		Data: make([]float64, rows*cols),
+	}
+	for i := range image.Data {
+		image.Data[i] = 100
+	}
+	for row := range size {
+		val := 0.0
+		if row >= size/2 {
+			val = 255
+		}
+		for col := range size {
+			image.Data[(y+row)*cols+x+col] = val
+		}
+	}
+	return image
+}
+
+func TestLoadHaarCascade(t *testing.T) {
+	tests := []struct {
+		name       string
+		filepath   string
+		wantStages int
+		wantErr    error
+	}{
+		{
+			name:       "valid cascade",
+			filepath:   "testdata/cascade.xml",
+			wantStages: 2,
+			wantErr:    nil,
+		},
+		{
+			name:       "tilted features are not supported",
+			filepath:   "testdata/tilted.xml",
+			wantStages: 0,
+			wantErr:    errUnsupportedCascade,
+		},
+		{
+			name:       "file that is not a cascade fails",
+			filepath:   "../data/s1/1.pgm",
+			wantStages: 0,
+			wantErr:    errCascadeReading,
+		},
+		{
+			name:       "invalid filepath",
+			filepath:   "nonexistent.xml",
+			wantStages: 0,
+			wantErr:    errFileOpening,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cascade, err := LoadHaarCascade(tt.filepath)
+			if err != tt.wantErr {
+				t.Fatalf("LoadHaarCascade(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && len(cascade.Stages) != tt.wantStages {
+				t.Errorf("LoadHaarCascade(): returned %d stages, want %d", len(cascade.Stages), tt.wantStages)
+			}
+		})
+	}
+}
+
+func TestIntegralImage(t *testing.T) {
+	image := m.Matrix{
+		Rows: 2,
+		Cols: 3,
+		Data: []float64{1, 2, 3, 4, 5, 6},
+	}
+
+	tests := []struct {
+		name string
+		rect Rect
+		want float64
+	}{
+		{
+			name: "whole image",
+			rect: Rect{X: 0, Y: 0, Width: 3, Height: 2},
+			want: 21,
+		},
+		{
+			name: "bottom right corner",
+			rect: Rect{X: 1, Y: 1, Width: 2, Height: 1},
+			want: 11,
+		},
+		{
+			name: "empty rectangle",
+			rect: Rect{X: 1, Y: 1, Width: 0, Height: 0},
+			want: 0,
+		},
+	}
+
+	integral := IntegralImage(image)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RectSum(integral, tt.rect)
+			if result != tt.want {
+				t.Errorf("RectSum(): got %f, want %f", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestHaarDetect(t *testing.T) {
+	cascade, err := LoadHaarCascade("testdata/cascade.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		image m.Matrix
+		opts  HaarOptions
+		want  []Rect
+	}{
+		{
+			name:  "pattern at window size is found",
+			image: createPatternImage(20, 20, 8, 6, 6),
+			opts:  HaarOptions{MinNeighbors: -1},
+			want:  []Rect{{X: 8, Y: 6, Width: 6, Height: 6}},
+		},
+		{
+			name:  "larger pattern is found from a smaller scale",
+			image: createPatternImage(40, 40, 12, 12, 12),
+			opts:  HaarOptions{ScaleFactor: 2, MinNeighbors: -1, MinSize: 12},
+			want:  []Rect{{X: 12, Y: 12, Width: 12, Height: 12}},
+		},
+		{
+			name:  "flat image has no faces",
+			image: createPatternImage(20, 20, 0, 0, 0),
+			opts:  HaarOptions{},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := cascade.Detect(tt.image, tt.opts)
+			if len(result) != len(tt.want) {
+				t.Fatalf("Detect(): returned %v, want %v", result, tt.want)
+			}
+			for i := range result {
+				if result[i] != tt.want[i] {
+					t.Errorf("Detect(): at index %d, got %+v, want %+v", i, result[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGroupRectangles(t *testing.T) {
+	tests := []struct {
+		name         string
+		rects        []Rect
+		minNeighbors int
+		want         []Rect
+	}{
+		{
+			name: "similar rectangles are averaged",
+			rects: []Rect{
+				{X: 10, Y: 10, Width: 20, Height: 20},
+				{X: 12, Y: 10, Width: 20, Height: 20},
+				{X: 80, Y: 80, Width: 20, Height: 20},
+			},
+			minNeighbors: 0,
+			want: []Rect{
+				{X: 11, Y: 10, Width: 20, Height: 20},
+				{X: 80, Y: 80, Width: 20, Height: 20},
+			},
+		},
+		{
+			name: "clusters without enough neighbors are dropped",
+			rects: []Rect{
+				{X: 10, Y: 10, Width: 20, Height: 20},
+				{X: 12, Y: 10, Width: 20, Height: 20},
+				{X: 80, Y: 80, Width: 20, Height: 20},
+			},
+			minNeighbors: 1,
+			want: []Rect{
+				{X: 11, Y: 10, Width: 20, Height: 20},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GroupRectangles(tt.rects, tt.minNeighbors, 0.2)
+			if len(result) != len(tt.want) {
+				t.Fatalf("GroupRectangles(): returned %v, want %v", result, tt.want)
+			}
+			for i := range result {
+				if result[i] != tt.want[i] {
+					t.Errorf("GroupRectangles(): at index %d, got %+v, want %+v", i, result[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCropFaces(t *testing.T) {
+	image := createPatternImage(20, 20, 8, 6, 6)
+
+	faces, err := CropFaces(image, []Rect{{X: 8, Y: 6, Width: 6, Height: 6}, {X: 18, Y: 18, Width: 6, Height: 6}}, 12, 12)
+	if err != nil {
+		t.Fatalf("CropFaces(): returned error %v", err)
+	}
+	if len(faces) != 2 {
+		t.Fatalf("CropFaces(): returned %d faces, want 2", len(faces))
+	}
+	for _, face := range faces {
+		if face.Rows != 12 || face.Cols != 12 {
+			t.Errorf("CropFaces(): returned face with size %dx%d, want 12x12", face.Rows, face.Cols)
+		}
+	}
+	if faces[0].Data[0] != 0 || faces[0].Data[len(faces[0].Data)-1] != 255 {
+		t.Errorf("CropFaces(): crop doesn't contain the pattern")
+	}
+}
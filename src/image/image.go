@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 var (
 	errFileOpening   = fmt.Errorf("error opening file")
 	errFileReading   = fmt.Errorf("error reading file")
+	errFileWriting   = fmt.Errorf("error writing file")
 	errWrongFaceSize = fmt.Errorf("size of the face was incorrect")
 )
 
@@ -78,6 +80,35 @@ func LoadPgmImage(filepath string) (*m.Matrix, error) {
 	return matrix, nil
 }
 
+// writes a 2D image matrix to a binary PGM (P5) file
+// values are rounded and clamped to the 0-255 range
+// returns an error if the file can't be written
+func SavePgmImage(filepath string, image m.Matrix) error {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return errFileOpening
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if _, err := fmt.Fprintf(writer, "P5\n%d %d\n255\n", image.Cols, image.Rows); err != nil {
+		return errFileWriting
+	}
+
+	rawData := make([]byte, len(image.Data))
+	for i, val := range image.Data {
+		rawData[i] = byte(clampPixel(math.Round(val)))
+	}
+	if _, err := writer.Write(rawData); err != nil {
+		return errFileWriting
+	}
+	if err := writer.Flush(); err != nil {
+		return errFileWriting
+	}
+
+	return nil
+}
+
 // converts a 2D image matrix into a 1D column vector
 func FlattenImage(image m.Matrix) m.Matrix {
 	result := m.Matrix{
@@ -89,30 +120,27 @@ func FlattenImage(image m.Matrix) m.Matrix {
 	return result
 }
 
-// calculates the average face from a slice of face matrices
+// calculates the average face from a slice of float32 or float64 face matrices
 // all input faces must have the same dimensions
 // returns a matrix representing the mean face
-func MeanOfImages(faces []m.Matrix) (m.Matrix, error) {
-	result := m.Matrix{
+func MeanOfImages[T m.Float](faces []m.Dense[T]) (m.Dense[T], error) {
+	result := m.Dense[T]{
 		Rows: faces[0].Rows,
 		Cols: faces[0].Cols,
-		Data: make([]float64, faces[0].Rows*faces[0].Cols),
+		Data: make([]T, faces[0].Rows*faces[0].Cols),
 	}
 
 	for _, face := range faces {
 		if face.Cols != result.Cols || face.Rows != result.Rows {
-			return m.Matrix{}, errWrongFaceSize
+			return m.Dense[T]{}, errWrongFaceSize
 		}
 
-		sum, err := m.Addition(result, face)
-		if err != nil {
-			return m.Matrix{}, err
+		if err := m.AddInPlace(result, face); err != nil {
+			return m.Dense[T]{}, err
 		}
-
-		result = sum
 	}
 
-	result = m.MultiplicationByScalar(result, 1/float64(len(faces)))
+	m.ScaleInPlace(result, 1/T(len(faces)))
 
 	return result, nil
 }
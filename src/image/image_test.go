@@ -37,6 +37,49 @@ func TestLoadPgmImage(t *testing.T) {
 	}
 }
 
+func TestSavePgmImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   m.Matrix
+		want    []float64
+		wantErr error
+	}{
+		{
+			name: "values are rounded and clamped",
+			image: m.Matrix{
+				Rows: 2,
+				Cols: 3,
+				Data: []float64{0, 10.4, 10.6, -5, 255, 300},
+			},
+			want:    []float64{0, 10, 11, 0, 255, 255},
+			wantErr: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := t.TempDir() + "/image.pgm"
+			err := SavePgmImage(path, tt.image)
+			if err != tt.wantErr {
+				t.Fatalf("SavePgmImage(): %v, want %v", err, tt.wantErr)
+			}
+
+			loaded, err := LoadPgmImage(path)
+			if err != nil {
+				t.Fatalf("SavePgmImage(): saved image can't be loaded: %v", err)
+			}
+			if loaded.Rows != tt.image.Rows || loaded.Cols != tt.image.Cols {
+				t.Errorf("SavePgmImage(): saved image has size %dx%d, want %dx%d", loaded.Rows, loaded.Cols, tt.image.Rows, tt.image.Cols)
+			}
+			for i := range tt.want {
+				if loaded.Data[i] != tt.want[i] {
+					t.Errorf("SavePgmImage(): at index %d, got %f, want %f", i, loaded.Data[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
 func TestFlattenImage(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -0,0 +1,159 @@
+package image
+
+import (
+	"fmt"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errWrongMaskSize = fmt.Errorf("size of the mask doesn't match the image")
+	errInvalidMask   = fmt.Errorf("mask doesn't keep any pixels")
+)
+
+// Mask selects which pixels of an image are used. Keep is stored in row-major order
+// like matrix data. If Drop is true masked pixels are removed when flattening,
+// otherwise they are set to zero and the vector keeps its full length
+type Mask struct {
+	Rows int
+	Cols int
+	Keep []bool
+	Drop bool
+}
+
+// constructs an elliptical mask centered in a rows * cols image
+// radiusX and radiusY are the semi-axes relative to half of the image width and height (0-1]
+// returns the mask or an error if the ellipse doesn't contain any pixel
+func EllipseMask(rows, cols int, radiusX, radiusY float64, drop bool) (Mask, error) {
+	mask := Mask{
+		Rows: rows,
+		Cols: cols,
+		Keep: make([]bool, rows*cols),
+		Drop: drop,
+	}
+
+	centerX := float64(cols-1) / 2
+	centerY := float64(rows-1) / 2
+	a := radiusX * float64(cols) / 2
+	b := radiusY * float64(rows) / 2
+	if a <= 0 || b <= 0 {
+		return Mask{}, errInvalidMask
+	}
+
+	kept := 0
+	for y := range rows {
+		dy := (float64(y) - centerY) / b
+		for x := range cols {
+			dx := (float64(x) - centerX) / a
+			if dx*dx+dy*dy <= 1 {
+				mask.Keep[y*cols+x] = true
+				kept++
+			}
+		}
+	}
+	if kept == 0 {
+		return Mask{}, errInvalidMask
+	}
+
+	return mask, nil
+}
+
+// loads a mask from a PGM image. Pixels brighter than the midpoint (> 127) are kept
+// returns the mask or an error if the file can't be read or it doesn't keep any pixels
+func LoadPgmMask(filepath string, drop bool) (Mask, error) {
+	img, err := LoadPgmImage(filepath)
+	if err != nil {
+		return Mask{}, err
+	}
+
+	mask := Mask{
+		Rows: img.Rows,
+		Cols: img.Cols,
+		Keep: make([]bool, len(img.Data)),
+		Drop: drop,
+	}
+
+	kept := 0
+	for i, val := range img.Data {
+		if val > 127 {
+			mask.Keep[i] = true
+			kept++
+		}
+	}
+	if kept == 0 {
+		return Mask{}, errInvalidMask
+	}
+
+	return mask, nil
+}
+
+// returns the number of elements in a vector flattened with the mask
+func (mask Mask) Size() int {
+	if !mask.Drop {
+		return mask.Rows * mask.Cols
+	}
+
+	kept := 0
+	for _, keep := range mask.Keep {
+		if keep {
+			kept++
+		}
+	}
+	return kept
+}
+
+// applies the mask to a 2D image and converts it into a 1D column vector
+// with a nil mask this is the same as FlattenImage
+// returns a new column vector or an error if the mask and image sizes differ
+func FlattenMasked(image m.Matrix, mask *Mask) (m.Matrix, error) {
+	if mask == nil {
+		return FlattenImage(image), nil
+	}
+	if image.Rows != mask.Rows || image.Cols != mask.Cols {
+		return m.Matrix{}, errWrongMaskSize
+	}
+
+	size := mask.Size()
+	result := m.Matrix{
+		Rows: size,
+		Cols: 1,
+		Data: make([]float64, 0, size),
+	}
+
+	for i, val := range image.Data {
+		switch {
+		case mask.Keep[i]:
+			result.Data = append(result.Data, val)
+		case !mask.Drop:
+			result.Data = append(result.Data, 0)
+		}
+	}
+
+	return result, nil
+}
+
+// applies the mask to a 2D image without flattening it. Masked pixels are always set to zero
+// since dropping them would break the rows and columns of the image
+// with a nil mask the image is returned as is. Returns a new image or an error if the mask and image sizes differ
+func ApplyMask(image m.Matrix, mask *Mask) (m.Matrix, error) {
+	if mask == nil {
+		return image, nil
+	}
+	if image.Rows != mask.Rows || image.Cols != mask.Cols {
+		return m.Matrix{}, errWrongMaskSize
+	}
+
+	result := m.Matrix{
+		Rows: image.Rows,
+		Cols: image.Cols,
+		Data: make([]float64, len(image.Data)),
+	}
+	for i, val := range image.Data {
+		if mask.Keep[i] {
+			result.Data[i] = val
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,239 @@
+package image
+
+import (
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestEllipseMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     int
+		cols     int
+		radiusX  float64
+		radiusY  float64
+		wantKeep []bool
+		wantErr  error
+	}{
+		{
+			name:    "corners are masked out",
+			rows:    3,
+			cols:    3,
+			radiusX: 0.8,
+			radiusY: 0.8,
+			wantKeep: []bool{
+				false, true, false,
+				true, true, true,
+				false, true, false,
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "narrow ellipse keeps only the middle column",
+			rows:    3,
+			cols:    3,
+			radiusX: 0.2,
+			radiusY: 1,
+			wantKeep: []bool{
+				false, true, false,
+				false, true, false,
+				false, true, false,
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "zero radius fails",
+			rows:     3,
+			cols:     3,
+			radiusX:  0,
+			radiusY:  1,
+			wantKeep: nil,
+			wantErr:  errInvalidMask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := EllipseMask(tt.rows, tt.cols, tt.radiusX, tt.radiusY, false)
+			if err != tt.wantErr {
+				t.Errorf("EllipseMask(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if len(mask.Keep) != len(tt.wantKeep) {
+				t.Fatalf("EllipseMask(): returned mask of size %d, want %d", len(mask.Keep), len(tt.wantKeep))
+			}
+			for i := range mask.Keep {
+				if mask.Keep[i] != tt.wantKeep[i] {
+					t.Errorf("EllipseMask(): at index %d, got %v, want %v", i, mask.Keep[i], tt.wantKeep[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPgmMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		filepath string
+		wantErr  error
+	}{
+		{
+			name:     "Invalid filepath",
+			filepath: "nonexistent.pgm",
+			wantErr:  errFileOpening,
+		},
+		{
+			name:     "Valid pgm file",
+			filepath: "../data/s1/1.pgm",
+			wantErr:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mask, err := LoadPgmMask(tt.filepath, true)
+			if err != tt.wantErr {
+				t.Errorf("LoadPgmMask(): %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (mask.Rows != 112 || mask.Cols != 92) {
+				t.Errorf("LoadPgmMask(): returned incorrect size %dx%d", mask.Rows, mask.Cols)
+			}
+		})
+	}
+}
+
+func TestFlattenMasked(t *testing.T) {
+	image := m.Matrix{
+		Rows: 2,
+		Cols: 2,
+		Data: []float64{1, 2, 3, 4},
+	}
+	keep := []bool{true, false, false, true}
+
+	tests := []struct {
+		name    string
+		mask    *Mask
+		image   m.Matrix
+		want    m.Matrix
+		wantErr error
+	}{
+		{
+			name:  "nil mask flattens the whole image",
+			mask:  nil,
+			image: image,
+			want: m.Matrix{
+				Rows: 4,
+				Cols: 1,
+				Data: []float64{1, 2, 3, 4},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "masked pixels are zeroed",
+			mask:  &Mask{Rows: 2, Cols: 2, Keep: keep, Drop: false},
+			image: image,
+			want: m.Matrix{
+				Rows: 4,
+				Cols: 1,
+				Data: []float64{1, 0, 0, 4},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "masked pixels are dropped",
+			mask:  &Mask{Rows: 2, Cols: 2, Keep: keep, Drop: true},
+			image: image,
+			want: m.Matrix{
+				Rows: 2,
+				Cols: 1,
+				Data: []float64{1, 4},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "mask with different size fails",
+			mask:    &Mask{Rows: 1, Cols: 4, Keep: keep, Drop: true},
+			image:   image,
+			want:    m.Matrix{},
+			wantErr: errWrongMaskSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FlattenMasked(tt.image, tt.mask)
+			if err != tt.wantErr {
+				t.Errorf("FlattenMasked(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if result.Cols != tt.want.Cols {
+				t.Errorf("FlattenMasked(): returned incorrect amount of colums")
+			}
+			if result.Rows != tt.want.Rows {
+				t.Errorf("FlattenMasked(): returned incorrect amount of rows")
+			}
+			for i := range result.Data {
+				if i < len(tt.want.Data) && result.Data[i] != tt.want.Data[i] {
+					t.Errorf("FlattenMasked(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyMask(t *testing.T) {
+	image := m.Matrix{
+		Rows: 2,
+		Cols: 2,
+		Data: []float64{1, 2, 3, 4},
+	}
+	keep := []bool{true, false, false, true}
+
+	tests := []struct {
+		name    string
+		mask    *Mask
+		want    m.Matrix
+		wantErr error
+	}{
+		{
+			name:    "nil mask keeps the image",
+			mask:    nil,
+			want:    image,
+			wantErr: nil,
+		},
+		{
+			name:    "masked pixels are zeroed",
+			mask:    &Mask{Rows: 2, Cols: 2, Keep: keep, Drop: false},
+			want:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{1, 0, 0, 4}},
+			wantErr: nil,
+		},
+		{
+			name:    "masked pixels are zeroed even if the mask drops them",
+			mask:    &Mask{Rows: 2, Cols: 2, Keep: keep, Drop: true},
+			want:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{1, 0, 0, 4}},
+			wantErr: nil,
+		},
+		{
+			name:    "mask with different size fails",
+			mask:    &Mask{Rows: 1, Cols: 4, Keep: keep, Drop: true},
+			want:    m.Matrix{},
+			wantErr: errWrongMaskSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ApplyMask(image, tt.mask)
+			if err != tt.wantErr {
+				t.Errorf("ApplyMask(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if result.Rows != tt.want.Rows || result.Cols != tt.want.Cols {
+				t.Fatalf("ApplyMask(): returned %dx%d image, want %dx%d", result.Rows, result.Cols, tt.want.Rows, tt.want.Cols)
+			}
+			for i := range tt.want.Data {
+				if result.Data[i] != tt.want.Data[i] {
+					t.Errorf("ApplyMask(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package image
+
+import (
+	"fmt"
+	"math"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errDifferentSizes = fmt.Errorf("images have different sizes")
+)
+
+// constants of SSIM for 8-bit images from Wang et al. (2004)
+const (
+	maxPixel   = 255.0
+	ssimC1     = (0.01 * maxPixel) * (0.01 * maxPixel)
+	ssimC2     = (0.03 * maxPixel) * (0.03 * maxPixel)
+	ssimWindow = 11
+	ssimSigma  = 1.5
+)
+
+// computes the peak signal-to-noise ratio in decibels between two 8-bit images
+// identical images return positive infinity
+// returns the PSNR or an error if the images have different sizes
+func PSNR(a, b m.Matrix) (float64, error) {
+	if a.Rows != b.Rows || a.Cols != b.Cols {
+		return 0, errDifferentSizes
+	}
+
+	mse := 0.0
+	for i := range a.Data {
+		diff := a.Data[i] - b.Data[i]
+		mse += diff * diff
+	}
+	mse /= float64(len(a.Data))
+	if mse == 0 {
+		return math.Inf(1), nil
+	}
+
+	return 10 * math.Log10(maxPixel*maxPixel/mse), nil
+}
+
+// computes the mean structural similarity index between two 8-bit images using an 11x11
+// gaussian window with standard deviation 1.5. Only windows fully inside the image are used
+// and the window shrinks for images smaller than 11 pixels. 1 means identical images
+// returns the SSIM or an error if the images have different sizes
+func SSIM(a, b m.Matrix) (float64, error) {
+	if a.Rows != b.Rows || a.Cols != b.Cols {
+		return 0, errDifferentSizes
+	}
+	if a.Rows == 0 || a.Cols == 0 {
+		return 0, errInvalidSize
+	}
+
+	size := min(ssimWindow, a.Rows, a.Cols)
+	if size%2 == 0 {
+		size--
+	}
+	weights := gaussianKernel(size, ssimSigma)
+
+	total := 0.0
+	count := 0
+	for y := 0; y+size <= a.Rows; y++ {
+		for x := 0; x+size <= a.Cols; x++ {
+			var meanA, meanB float64
+			for wy := range size {
+				for wx := range size {
+					w := weights[wy] * weights[wx]
+					meanA += w * a.Data[(y+wy)*a.Cols+x+wx]
+					meanB += w * b.Data[(y+wy)*b.Cols+x+wx]
+				}
+			}
+
+			var varA, varB, covariance float64
+			for wy := range size {
+				for wx := range size {
+					w := weights[wy] * weights[wx]
+					da := a.Data[(y+wy)*a.Cols+x+wx] - meanA
+					db := b.Data[(y+wy)*b.Cols+x+wx] - meanB
+					varA += w * da * da
+					varB += w * db * db
+					covariance += w * da * db
+				}
+			}
+
+			total += ((2*meanA*meanB + ssimC1) * (2*covariance + ssimC2)) /
+				((meanA*meanA + meanB*meanB + ssimC1) * (varA + varB + ssimC2))
+			count++
+		}
+	}
+
+	return total / float64(count), nil
+}
+
+// returns a normalized 1D gaussian kernel of the given odd size
+func gaussianKernel(size int, sigma float64) []float64 {
+	kernel := make([]float64, size)
+	center := float64(size / 2)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i) - center
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+	return kernel
+}
@@ -0,0 +1,115 @@
+package image
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestPSNR(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       m.Matrix
+		b       m.Matrix
+		want    float64
+		wantErr error
+	}{
+		{
+			name:    "identical images have infinite PSNR",
+			a:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{10, 20}},
+			b:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{10, 20}},
+			want:    math.Inf(1),
+			wantErr: nil,
+		},
+		{
+			name:    "mean squared error of 1",
+			a:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{10, 20}},
+			b:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{11, 19}},
+			want:    48.1308036087,
+			wantErr: nil,
+		},
+		{
+			name:    "different sizes fail",
+			a:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{10, 20}},
+			b:       m.Matrix{Rows: 2, Cols: 1, Data: []float64{10, 20}},
+			want:    0,
+			wantErr: errDifferentSizes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PSNR(tt.a, tt.b)
+			if err != tt.wantErr {
+				t.Errorf("PSNR(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if result != tt.want && math.Abs(result-tt.want) > EPSILON {
+				t.Errorf("PSNR(): got %f, want %f", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSIM(t *testing.T) {
+	face, err := LoadPgmImage("../data/s1/1.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	brighter := AdjustBrightnessContrast(*face, 30, 1)
+	flat := AdjustBrightnessContrast(*face, 0, 0)
+
+	tests := []struct {
+		name    string
+		a       m.Matrix
+		b       m.Matrix
+		wantMin float64
+		wantMax float64
+		wantErr error
+	}{
+		{
+			name:    "identical images have SSIM 1",
+			a:       *face,
+			b:       *face,
+			wantMin: 1,
+			wantMax: 1,
+			wantErr: nil,
+		},
+		{
+			name:    "brightness change keeps the structure",
+			a:       *face,
+			b:       brighter,
+			wantMin: 0.8,
+			wantMax: 1,
+			wantErr: nil,
+		},
+		{
+			name:    "flat image loses the structure",
+			a:       *face,
+			b:       flat,
+			wantMin: 0,
+			wantMax: 0.6,
+			wantErr: nil,
+		},
+		{
+			name:    "different sizes fail",
+			a:       *face,
+			b:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{10, 20}},
+			wantMin: 0,
+			wantMax: 0,
+			wantErr: errDifferentSizes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SSIM(tt.a, tt.b)
+			if err != tt.wantErr {
+				t.Errorf("SSIM(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if result < tt.wantMin-EPSILON || result > tt.wantMax+EPSILON {
+				t.Errorf("SSIM(): got %f, want between %f and %f", result, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
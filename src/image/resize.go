@@ -0,0 +1,59 @@
+package image
+
+import (
+	"fmt"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errInvalidSize = fmt.Errorf("invalid image size")
+	errOutOfBounds = fmt.Errorf("region is outside of the image")
+)
+
+// resizes a 2D image to rows * cols pixels using bilinear interpolation
+// returns a new matrix containing the result or an error if the size isn't positive
+func Resize(image m.Matrix, rows, cols int) (m.Matrix, error) {
+	if rows < 1 || cols < 1 {
+		return m.Matrix{}, errInvalidSize
+	}
+
+	result := m.Matrix{
+		Rows: rows,
+		Cols: cols,
+		Data: make([]float64, rows*cols),
+	}
+
+	// pixel centers of the result are mapped onto pixel centers of the source
+	scaleY := float64(image.Rows) / float64(rows)
+	scaleX := float64(image.Cols) / float64(cols)
+
+	for y := range rows {
+		srcY := (float64(y)+0.5)*scaleY - 0.5
+		for x := range cols {
+			srcX := (float64(x)+0.5)*scaleX - 0.5
+			result.Data[y*cols+x] = bilinear(image, srcX, srcY)
+		}
+	}
+
+	return result, nil
+}
+
+// copies a width * height region with the top left corner at (x, y) out of a 2D image
+// returns a new matrix containing the region or an error if it doesn't fit inside the image
+func Crop(image m.Matrix, x, y, width, height int) (m.Matrix, error) {
+	if width < 1 || height < 1 {
+		return m.Matrix{}, errInvalidSize
+	}
+	if x < 0 || y < 0 || x+width > image.Cols || y+height > image.Rows {
+		return m.Matrix{}, errOutOfBounds
+	}
+
+	region, err := image.Slice(y, x, height, width)
+	if err != nil {
+		return m.Matrix{}, err
+	}
+
+	return region.Copy(), nil
+}
@@ -0,0 +1,142 @@
+package image
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestResize(t *testing.T) {
+	image := m.Matrix{
+		Rows: 2,
+		Cols: 2,
+		Data: []float64{0, 10, 20, 30},
+	}
+
+	tests := []struct {
+		name    string
+		rows    int
+		cols    int
+		want    m.Matrix
+		wantErr error
+	}{
+		{
+			name: "same size keeps the image",
+			rows: 2,
+			cols: 2,
+			want: image,
+		},
+		{
+			name: "downscaling to one pixel averages the image",
+			rows: 1,
+			cols: 1,
+			want: m.Matrix{
+				Rows: 1,
+				Cols: 1,
+				Data: []float64{15},
+			},
+		},
+		{
+			name: "upscaling keeps the corner pixels",
+			rows: 4,
+			cols: 4,
+			want: m.Matrix{
+				Rows: 4,
+				Cols: 4,
+				Data: []float64{
+					0, 2.5, 7.5, 10,
+					5, 7.5, 12.5, 15,
+					15, 17.5, 22.5, 25,
+					20, 22.5, 27.5, 30,
+				},
+			},
+		},
+		{
+			name:    "zero size fails",
+			rows:    0,
+			cols:    2,
+			want:    m.Matrix{},
+			wantErr: errInvalidSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Resize(image, tt.rows, tt.cols)
+			if err != tt.wantErr {
+				t.Errorf("Resize(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if result.Rows != tt.want.Rows || result.Cols != tt.want.Cols {
+				t.Fatalf("Resize(): returned size %dx%d, want %dx%d", result.Rows, result.Cols, tt.want.Rows, tt.want.Cols)
+			}
+			for i := range tt.want.Data {
+				if math.Abs(result.Data[i]-tt.want.Data[i]) > EPSILON {
+					t.Errorf("Resize(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCrop(t *testing.T) {
+	image := m.Matrix{
+		Rows: 3,
+		Cols: 3,
+		Data: []float64{
+			1, 2, 3,
+			4, 5, 6,
+			7, 8, 9,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		x       int
+		y       int
+		width   int
+		height  int
+		want    m.Matrix
+		wantErr error
+	}{
+		{
+			name:   "bottom right corner",
+			x:      1,
+			y:      1,
+			width:  2,
+			height: 2,
+			want: m.Matrix{
+				Rows: 2,
+				Cols: 2,
+				Data: []float64{5, 6, 8, 9},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "region outside the image fails",
+			x:       2,
+			y:       0,
+			width:   2,
+			height:  2,
+			want:    m.Matrix{},
+			wantErr: errOutOfBounds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Crop(image, tt.x, tt.y, tt.width, tt.height)
+			if err != tt.wantErr {
+				t.Errorf("Crop(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			if result.Rows != tt.want.Rows || result.Cols != tt.want.Cols {
+				t.Fatalf("Crop(): returned size %dx%d, want %dx%d", result.Rows, result.Cols, tt.want.Rows, tt.want.Cols)
+			}
+			for i := range tt.want.Data {
+				if result.Data[i] != tt.want.Data[i] {
+					t.Errorf("Crop(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"face_recognition/cli"
+	"face_recognition/image"
 	r "face_recognition/recognition"
 )
 
@@ -38,6 +39,32 @@ func main() {
 	args := os.Args[1:]
 	var dataSets []int
 	var testImage []int
+	var opts r.Options
+
+	if len(args) > 0 && args[0] == "detect" {
+		if err := cli.Detect(args[1:]); err != nil {
+			fmt.Println(err)
+		}
+		os.Exit(0)
+	}
+	if len(args) > 0 && args[0] == "reconstruct" {
+		if err := cli.Reconstruct(args[1:]); err != nil {
+			fmt.Println(err)
+		}
+		os.Exit(0)
+	}
+	if len(args) > 0 && args[0] == "spectrum" {
+		if err := cli.Spectrum(args[1:]); err != nil {
+			fmt.Println(err)
+		}
+		os.Exit(0)
+	}
+	if len(args) > 0 && args[0] == "evaluate" {
+		if err := cli.Evaluate(args[1:]); err != nil {
+			fmt.Println(err)
+		}
+		os.Exit(0)
+	}
 
 	// check for given arguments
 	for i, flag := range args {
@@ -77,6 +104,48 @@ func main() {
 
 			imagesFromEachSet = num
 			interactiveMode = false
+		case "-m":
+			value, err := cli.LoadMask(args[i+1])
+			if err != nil {
+				panic(err)
+			}
+			opts.Mask = value
+			interactiveMode = false
+		case "-a":
+			seed, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil {
+				panic(err)
+			}
+			augmentation := image.DefaultAugmentation(seed)
+			opts.Augment = &augmentation
+			interactiveMode = false
+		case "-b":
+			backend, err := cli.ParseBackend(args[i+1])
+			if err != nil {
+				panic(err)
+			}
+			opts.Backend = backend
+			interactiveMode = false
+		case "-r":
+			method, err := cli.ParseMethod(args[i+1])
+			if err != nil {
+				panic(err)
+			}
+			opts.Method = method
+			interactiveMode = false
+		case "-g":
+			kernel, err := cli.ParseKernel(args[i+1])
+			if err != nil {
+				panic(err)
+			}
+			opts.Kernel = kernel
+			interactiveMode = false
+		case "-e":
+			opts.Export = args[i+1]
+			interactiveMode = false
+		case "-f":
+			opts.Float32 = true
+			interactiveMode = false
 		case "-s":
 			j := i + 1
 			for j < len(args) && !strings.HasPrefix(args[j], "-") {
@@ -110,7 +179,7 @@ func main() {
 
 	// decide to run in interactive mode or not
 	if !interactiveMode {
-		matchIndex, similarity, err := r.Run(timing, dataSets, testImage[:2], k, imagesFromEachSet, "./")
+		matchIndex, similarity, err := r.RunWithOptions(timing, dataSets, testImage[:2], k, imagesFromEachSet, "./", opts)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(0)
@@ -124,6 +193,6 @@ func main() {
 		fmt.Println("closest match with: set", matchDataSet, "| image", matchImgNum)
 		fmt.Printf("similarity: %.1f%% \n", similarity)
 	} else {
-		cli.Interactive(dataSets, testImage, k, imagesFromEachSet, timing)
+		cli.Interactive(dataSets, testImage, k, imagesFromEachSet, timing, opts)
 	}
 }
@@ -0,0 +1,371 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// define possible errors
+var (
+	errNotSquare           = fmt.Errorf("matrix is not square")
+	errSingular            = fmt.Errorf("matrix is singular")
+	errNotPositiveDefinite = fmt.Errorf("matrix is not symmetric positive definite")
+)
+
+// LU decomposition PA = LU of a square matrix. The unit lower triangular L (without its diagonal)
+// and the upper triangular U are stored in the same matrix
+type LUDecomposition[T Float] struct {
+	Factors Dense[T] // L below the diagonal, U on and above it
+	Pivots  []int    // row i of PA is row Pivots[i] of A
+	sign    T        // determinant of P
+}
+
+// Cholesky decomposition A = LLᵀ of a symmetric positive definite matrix
+type CholeskyDecomposition[T Float] struct {
+	L Dense[T] // lower triangular factor
+}
+
+// computes the LU decomposition of a square matrix with partial pivoting
+// returns the decomposition or an error if the matrix is not square or is singular
+func LU[T Float](A Dense[T]) (LUDecomposition[T], error) {
+	if A.Rows != A.Cols {
+		return LUDecomposition[T]{}, errNotSquare
+	}
+
+	n := A.Rows
+	factors := Dense[T]{
+		Rows: n,
+		Cols: n,
+		Data: make([]T, n*n),
+	}
+	copy(factors.Data, A.Data)
+	pivots := make([]int, n)
+	for i := range pivots {
+		pivots[i] = i
+	}
+	var sign T = 1
+
+	// pivots this small compared to the largest element are rounding noise
+	tolerance := float64(NormInf(A.Data)) * float64(n) * Epsilon[T]()
+
+	for k := range n {
+		pivotRow := k
+		for i := k + 1; i < n; i++ {
+			if Abs(factors.Data[i*n+k]) > Abs(factors.Data[pivotRow*n+k]) {
+				pivotRow = i
+			}
+		}
+		if float64(Abs(factors.Data[pivotRow*n+k])) <= tolerance {
+			return LUDecomposition[T]{}, errSingular
+		}
+
+		if pivotRow != k {
+			rowK := factors.Row(k)
+			rowP := factors.Row(pivotRow)
+			for j := range rowK {
+				rowK[j], rowP[j] = rowP[j], rowK[j]
+			}
+			pivots[k], pivots[pivotRow] = pivots[pivotRow], pivots[k]
+			sign = -sign
+		}
+
+		pivot := factors.Data[k*n+k]
+		rowK := factors.Row(k)
+		for i := k + 1; i < n; i++ {
+			rowI := factors.Row(i)
+			rowI[k] /= pivot
+			Axpy(-rowI[k], rowK[k+1:], rowI[k+1:])
+		}
+	}
+
+	return LUDecomposition[T]{Factors: factors, Pivots: pivots, sign: sign}, nil
+}
+
+// solves AX = B for X using the decomposition
+// returns X or an error if B has the wrong number of rows
+func (lu LUDecomposition[T]) Solve(B Dense[T]) (Dense[T], error) {
+	n := lu.Factors.Rows
+	if B.Rows != n {
+		return Dense[T]{}, errIncorrectSize
+	}
+
+	X := Dense[T]{
+		Rows: n,
+		Cols: B.Cols,
+		Data: make([]T, n*B.Cols),
+	}
+	for i, row := range lu.Pivots {
+		copy(X.Row(i), B.Row(row))
+	}
+
+	// forward substitution with the unit lower triangular L
+	for i := range n {
+		rowI := X.Row(i)
+		for k := range i {
+			Axpy(-lu.Factors.Data[i*n+k], X.Row(k), rowI)
+		}
+	}
+
+	// back substitution with U
+	for i := n - 1; i >= 0; i-- {
+		rowI := X.Row(i)
+		for k := i + 1; k < n; k++ {
+			Axpy(-lu.Factors.Data[i*n+k], X.Row(k), rowI)
+		}
+		ScaleInPlace(Dense[T]{Rows: 1, Cols: B.Cols, Data: rowI}, 1/lu.Factors.Data[i*n+i])
+	}
+
+	return X, nil
+}
+
+// solves AᵀX = B for X using the decomposition. Used by the condition number estimate
+// returns X or an error if B has the wrong number of rows
+func (lu LUDecomposition[T]) solveTranspose(B Dense[T]) (Dense[T], error) {
+	n := lu.Factors.Rows
+	if B.Rows != n {
+		return Dense[T]{}, errIncorrectSize
+	}
+
+	// Aᵀ = UᵀLᵀP so first Uᵀz = b, then Lᵀw = z and finally x = Pᵀw
+	Z := Dense[T]{
+		Rows: n,
+		Cols: B.Cols,
+		Data: make([]T, n*B.Cols),
+	}
+	copy(Z.Data, B.Data)
+
+	for i := range n {
+		rowI := Z.Row(i)
+		for k := range i {
+			Axpy(-lu.Factors.Data[k*n+i], Z.Row(k), rowI)
+		}
+		ScaleInPlace(Dense[T]{Rows: 1, Cols: B.Cols, Data: rowI}, 1/lu.Factors.Data[i*n+i])
+	}
+	for i := n - 1; i >= 0; i-- {
+		rowI := Z.Row(i)
+		for k := i + 1; k < n; k++ {
+			Axpy(-lu.Factors.Data[k*n+i], Z.Row(k), rowI)
+		}
+	}
+
+	X := Dense[T]{
+		Rows: n,
+		Cols: B.Cols,
+		Data: make([]T, n*B.Cols),
+	}
+	for i, row := range lu.Pivots {
+		copy(X.Row(row), Z.Row(i))
+	}
+
+	return X, nil
+}
+
+// computes the determinant of the decomposed matrix
+func (lu LUDecomposition[T]) Det() T {
+	n := lu.Factors.Rows
+	det := lu.sign
+	for i := range n {
+		det *= lu.Factors.Data[i*n+i]
+	}
+
+	return det
+}
+
+// computes the inverse of the decomposed matrix
+// returns a new matrix containing the result
+func (lu LUDecomposition[T]) Inverse() Dense[T] {
+	// the identity always has the right size so the error can be ignored
+	inverse, _ := lu.Solve(IdentityOf[T](lu.Factors.Rows))
+	return inverse
+}
+
+// estimates the 1-norm condition number ||A|| * ||A⁻¹|| of the decomposed matrix A with
+// Hager's method, which needs a few solves instead of the full inverse. The estimate is
+// a lower bound that is usually exact or within a small factor. Large values mean that
+// solutions with the matrix lose about log10(condition) digits of accuracy
+func (lu LUDecomposition[T]) Condition(A Dense[T]) (T, error) {
+	n := lu.Factors.Rows
+	if A.Rows != n || A.Cols != n {
+		return 0, errIncorrectSize
+	}
+
+	// 1-norm of A is its largest absolute column sum
+	var normA T
+	for j := range n {
+		var sum T
+		for i := range n {
+			sum += Abs(A.Data[i*n+j])
+		}
+		normA = max(normA, sum)
+	}
+
+	x := Dense[T]{Rows: n, Cols: 1, Data: make([]T, n)}
+	for i := range x.Data {
+		x.Data[i] = 1 / T(n)
+	}
+
+	var estimate T
+	for range 5 {
+		y, err := lu.Solve(x)
+		if err != nil {
+			return 0, err
+		}
+		estimate = Norm1(y.Data)
+
+		for i, num := range y.Data {
+			if num >= 0 {
+				y.Data[i] = 1
+			} else {
+				y.Data[i] = -1
+			}
+		}
+		z, err := lu.solveTranspose(y)
+		if err != nil {
+			return 0, err
+		}
+
+		// the estimate can't improve if no element of z is larger than zᵀx
+		largest := 0
+		for i := range z.Data {
+			if Abs(z.Data[i]) > Abs(z.Data[largest]) {
+				largest = i
+			}
+		}
+		if Abs(z.Data[largest]) <= Dot(z.Data, x.Data) {
+			break
+		}
+		clear(x.Data)
+		x.Data[largest] = 1
+	}
+
+	return normA * estimate, nil
+}
+
+// computes the Cholesky decomposition of a symmetric positive definite matrix
+// only the lower triangle of A is read
+// returns the decomposition or an error if the matrix is not square or not positive definite
+func Cholesky[T Float](A Dense[T]) (CholeskyDecomposition[T], error) {
+	if A.Rows != A.Cols {
+		return CholeskyDecomposition[T]{}, errNotSquare
+	}
+
+	n := A.Rows
+	L := Dense[T]{
+		Rows: n,
+		Cols: n,
+		Data: make([]T, n*n),
+	}
+
+	for i := range n {
+		rowI := L.Row(i)
+		for j := range i + 1 {
+			rowJ := L.Row(j)
+			sum := A.Data[i*n+j] - Dot(rowI[:j], rowJ[:j])
+			if i == j {
+				if sum <= 0 || math.IsNaN(float64(sum)) {
+					return CholeskyDecomposition[T]{}, errNotPositiveDefinite
+				}
+				rowI[i] = T(math.Sqrt(float64(sum)))
+			} else {
+				rowI[j] = sum / rowJ[j]
+			}
+		}
+	}
+
+	return CholeskyDecomposition[T]{L: L}, nil
+}
+
+// solves AX = B for X using the decomposition
+// returns X or an error if B has the wrong number of rows
+func (c CholeskyDecomposition[T]) Solve(B Dense[T]) (Dense[T], error) {
+	n := c.L.Rows
+	if B.Rows != n {
+		return Dense[T]{}, errIncorrectSize
+	}
+
+	X := Dense[T]{
+		Rows: n,
+		Cols: B.Cols,
+		Data: make([]T, n*B.Cols),
+	}
+	copy(X.Data, B.Data)
+
+	// Ly = b and then Lᵀx = y
+	for i := range n {
+		rowI := X.Row(i)
+		for k := range i {
+			Axpy(-c.L.Data[i*n+k], X.Row(k), rowI)
+		}
+		ScaleInPlace(Dense[T]{Rows: 1, Cols: B.Cols, Data: rowI}, 1/c.L.Data[i*n+i])
+	}
+	for i := n - 1; i >= 0; i-- {
+		rowI := X.Row(i)
+		for k := i + 1; k < n; k++ {
+			Axpy(-c.L.Data[k*n+i], X.Row(k), rowI)
+		}
+		ScaleInPlace(Dense[T]{Rows: 1, Cols: B.Cols, Data: rowI}, 1/c.L.Data[i*n+i])
+	}
+
+	return X, nil
+}
+
+// computes the determinant of the decomposed matrix
+func (c CholeskyDecomposition[T]) Det() T {
+	n := c.L.Rows
+	var det T = 1
+	for i := range n {
+		det *= c.L.Data[i*n+i]
+	}
+
+	return det * det
+}
+
+// solves AX = B for X with LU decomposition
+// returns X or an error if the sizes are incompatible or A is singular
+func Solve[T Float](A, B Dense[T]) (Dense[T], error) {
+	lu, err := LU(A)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+
+	return lu.Solve(B)
+}
+
+// computes the inverse of a square matrix with LU decomposition
+// returns a new matrix containing the result or an error if A is not square or is singular
+func Inverse[T Float](A Dense[T]) (Dense[T], error) {
+	lu, err := LU(A)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+
+	return lu.Inverse(), nil
+}
+
+// computes the determinant of a square matrix. A singular matrix has determinant 0
+// returns the determinant or an error if the matrix is not square
+func Det[T Float](A Dense[T]) (T, error) {
+	lu, err := LU(A)
+	if err == errSingular {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return lu.Det(), nil
+}
+
+// estimates the 1-norm condition number of a square matrix
+// returns the estimate, +Inf for a singular matrix, or an error if the matrix is not square
+func ConditionNumber[T Float](A Dense[T]) (T, error) {
+	lu, err := LU(A)
+	if err == errSingular {
+		return T(math.Inf(1)), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return lu.Condition(A)
+}
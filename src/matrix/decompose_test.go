@@ -0,0 +1,240 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLU(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       Matrix
+		wantDet float64
+		wantErr error
+	}{
+		{
+			name:    "matrix that needs pivoting",
+			A:       Matrix{Rows: 3, Cols: 3, Data: []float64{0, 2, 1, 1, 1, 1, 2, 1, 3}},
+			wantDet: -3,
+			wantErr: nil,
+		},
+		{
+			name:    "random matrix",
+			A:       createRandomMatrix(20, 20, 7),
+			wantDet: math.NaN(),
+			wantErr: nil,
+		},
+		{
+			name:    "singular matrix fails",
+			A:       Matrix{Rows: 3, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+			wantErr: errSingular,
+		},
+		{
+			name:    "non-square matrix fails",
+			A:       Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}},
+			wantErr: errNotSquare,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lu, err := LU(tt.A)
+			if err != tt.wantErr {
+				t.Fatalf("LU(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// PA must equal LU
+			n := tt.A.Rows
+			for i := range n {
+				for j := range n {
+					var sum float64
+					for k := range min(i, j) + 1 {
+						l := lu.Factors.Data[i*n+k]
+						if k == i {
+							l = 1
+						}
+						sum += l * lu.Factors.Data[k*n+j]
+					}
+					want := tt.A.Data[lu.Pivots[i]*n+j]
+					if math.Abs(sum-want) > EPSILON*100 {
+						t.Errorf("LU(): PA and LU differ at (%d, %d), got %f, want %f", i, j, sum, want)
+					}
+				}
+			}
+
+			if !math.IsNaN(tt.wantDet) && math.Abs(lu.Det()-tt.wantDet) > EPSILON {
+				t.Errorf("Det(): got %f, want %f", lu.Det(), tt.wantDet)
+			}
+		})
+	}
+}
+
+func TestSolveAndInverse(t *testing.T) {
+	A := createRandomMatrix(30, 30, 1)
+	B := createRandomMatrix(30, 4, 2)
+
+	X, err := Solve(A, B)
+	if err != nil {
+		t.Fatalf("Solve(): returned error %v", err)
+	}
+	result, _ := Multiplication(A, X)
+	for i := range B.Data {
+		if math.Abs(result.Data[i]-B.Data[i]) > EPSILON {
+			t.Fatalf("Solve(): AX differs from B at index %d, got %f, want %f", i, result.Data[i], B.Data[i])
+		}
+	}
+
+	inverse, err := Inverse(A)
+	if err != nil {
+		t.Fatalf("Inverse(): returned error %v", err)
+	}
+	identity, _ := Multiplication(A, inverse)
+	want := Identity(30)
+	for i := range want.Data {
+		if math.Abs(identity.Data[i]-want.Data[i]) > EPSILON {
+			t.Fatalf("Inverse(): A * inverse differs from identity at index %d, got %f", i, identity.Data[i])
+		}
+	}
+
+	if _, err := Solve(A, createRandomMatrix(29, 1, 3)); err != errIncorrectSize {
+		t.Errorf("Solve(): returned wrong error %v, want %v", err, errIncorrectSize)
+	}
+	singular := Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 2, 4}}
+	if _, err := Inverse(singular); err != errSingular {
+		t.Errorf("Inverse(): returned wrong error %v, want %v", err, errSingular)
+	}
+}
+
+func TestDet(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       Matrix
+		want    float64
+		wantErr error
+	}{
+		{
+			name: "2x2 matrix",
+			A:    Matrix{Rows: 2, Cols: 2, Data: []float64{3, 8, 4, 6}},
+			want: -14,
+		},
+		{
+			name: "identity",
+			A:    Identity(5),
+			want: 1,
+		},
+		{
+			name: "singular matrix has zero determinant",
+			A:    Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 2, 4}},
+			want: 0,
+		},
+		{
+			name:    "non-square matrix fails",
+			A:       Matrix{Rows: 1, Cols: 2, Data: []float64{1, 2}},
+			want:    0,
+			wantErr: errNotSquare,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			det, err := Det(tt.A)
+			if err != tt.wantErr {
+				t.Errorf("Det(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if math.Abs(det-tt.want) > EPSILON {
+				t.Errorf("Det(): got %f, want %f", det, tt.want)
+			}
+		})
+	}
+}
+
+func TestCholesky(t *testing.T) {
+	// AᵀA + I is symmetric positive definite
+	A := Gram(createRandomMatrix(12, 8, 4))
+	for i := range A.Rows {
+		A.Data[i*A.Cols+i] += 1
+	}
+
+	c, err := Cholesky(A)
+	if err != nil {
+		t.Fatalf("Cholesky(): returned error %v", err)
+	}
+	LLT, _ := MulTransB(c.L, c.L)
+	for i := range A.Data {
+		if math.Abs(LLT.Data[i]-A.Data[i]) > EPSILON*100 {
+			t.Fatalf("Cholesky(): LLᵀ differs from A at index %d, got %f, want %f", i, LLT.Data[i], A.Data[i])
+		}
+	}
+
+	B := createRandomMatrix(8, 2, 5)
+	X, err := c.Solve(B)
+	if err != nil {
+		t.Fatalf("Solve(): returned error %v", err)
+	}
+	want, _ := Solve(A, B)
+	for i := range want.Data {
+		if math.Abs(X.Data[i]-want.Data[i]) > EPSILON {
+			t.Errorf("Solve(): Cholesky and LU differ at index %d, got %f, want %f", i, X.Data[i], want.Data[i])
+		}
+	}
+
+	det, _ := Det(A)
+	if math.Abs(c.Det()-det) > EPSILON*math.Abs(det) {
+		t.Errorf("Det(): Cholesky got %f, LU got %f", c.Det(), det)
+	}
+
+	indefinite := Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 2, 1}}
+	if _, err := Cholesky(indefinite); err != errNotPositiveDefinite {
+		t.Errorf("Cholesky(): returned wrong error %v, want %v", err, errNotPositiveDefinite)
+	}
+}
+
+func TestConditionNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		A    Matrix
+		want float64
+	}{
+		{
+			name: "identity is perfectly conditioned",
+			A:    Identity(4),
+			want: 1,
+		},
+		{
+			name: "diagonal matrix",
+			A:    Matrix{Rows: 3, Cols: 3, Data: []float64{100, 0, 0, 0, 1, 0, 0, 0, 0.5}},
+			want: 200,
+		},
+		{
+			name: "2x2 matrix",
+			A:    Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			want: 21,
+		},
+		{
+			name: "singular matrix",
+			A:    Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 2, 4}},
+			want: math.Inf(1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := ConditionNumber(tt.A)
+			if err != nil {
+				t.Fatalf("ConditionNumber(): returned error %v", err)
+			}
+			if math.IsInf(tt.want, 1) {
+				if !math.IsInf(condition, 1) {
+					t.Errorf("ConditionNumber(): got %f, want +Inf", condition)
+				}
+				return
+			}
+			if math.Abs(condition-tt.want) > EPSILON*tt.want {
+				t.Errorf("ConditionNumber(): got %f, want %f", condition, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+)
+
+// the functions in this file write their result into an existing matrix instead of
+// allocating a new one. dst must already have the size of the result
+
+// multiplies all elements of A by a scalar value and stores the result in dst
+// dst may be A itself. Returns an error if the sizes don't match
+func MultiplicationByScalarInto[T Float](dst, A Dense[T], scalar T) error {
+	if dst.Rows != A.Rows || dst.Cols != A.Cols {
+		return errIncorrectSize
+	}
+
+	for i, num := range A.Data {
+		dst.Data[i] = num * scalar
+	}
+
+	return nil
+}
+
+// multiplies A and B together and stores the result in dst
+// dst must not share data with A or B. Returns an error if dimensions are incompatible
+func MultiplicationInto[T Float](dst, A, B Dense[T]) error {
+	if A.Cols != B.Rows || dst.Rows != A.Rows || dst.Cols != B.Cols {
+		return errIncorrectSize
+	}
+
+	if A.Rows*A.Cols*B.Cols < parallelThreshold {
+		multiplySimple(dst, A, B)
+		return nil
+	}
+
+	// the blocked kernel accumulates into dst
+	clear(dst.Data)
+
+	workers := min(runtime.GOMAXPROCS(0), A.Rows)
+	rowsPerWorker := (A.Rows + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for start := 0; start < A.Rows; start += rowsPerWorker {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			multiplyBlocked(dst, A, B, start, end)
+		}(start, min(start+rowsPerWorker, A.Rows))
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// adds A and B together and stores the result in dst
+// dst may be A or B. Returns an error if dimensions are incompatible
+func AdditionInto[T Float](dst, A, B Dense[T]) error {
+	if A.Rows != B.Rows || A.Cols != B.Cols || dst.Rows != A.Rows || dst.Cols != A.Cols {
+		return errIncorrectSize
+	}
+
+	for n := range A.Data {
+		dst.Data[n] = A.Data[n] + B.Data[n]
+	}
+
+	return nil
+}
+
+// subtracts B from A and stores the result in dst
+// dst may be A or B. Returns an error if dimensions are incompatible
+func SubractionInto[T Float](dst, A, B Dense[T]) error {
+	if A.Rows != B.Rows || A.Cols != B.Cols || dst.Rows != A.Rows || dst.Cols != A.Cols {
+		return errIncorrectSize
+	}
+
+	for n := range A.Data {
+		dst.Data[n] = A.Data[n] - B.Data[n]
+	}
+
+	return nil
+}
+
+// transposes A and stores the result in dst
+// dst must not share data with A. Returns an error if the sizes don't match
+func TransposeInto[T Float](dst, A Dense[T]) error {
+	if dst.Rows != A.Cols || dst.Cols != A.Rows {
+		return errIncorrectSize
+	}
+
+	for n := range A.Rows {
+		for m := range A.Cols {
+			dst.Data[m*A.Rows+n] = A.Data[n*A.Cols+m]
+		}
+	}
+
+	return nil
+}
+
+// adds B to A in place
+// returns an error if dimensions are incompatible
+func AddInPlace[T Float](A, B Dense[T]) error {
+	return AdditionInto(A, A, B)
+}
+
+// subtracts B from A in place
+// returns an error if dimensions are incompatible
+func SubtractInPlace[T Float](A, B Dense[T]) error {
+	return SubractionInto(A, A, B)
+}
+
+// multiplies all elements of A by a scalar value in place
+func ScaleInPlace[T Float](A Dense[T], scalar T) {
+	for i := range A.Data {
+		A.Data[i] *= scalar
+	}
+}
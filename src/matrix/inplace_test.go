@@ -0,0 +1,185 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAdditionAndSubractionInto(t *testing.T) {
+	tests := []struct {
+		name      string
+		dst       Matrix
+		A         Matrix
+		B         Matrix
+		wantSum   []float64
+		wantDiff  []float64
+		wantErr   error
+		aliasDstA bool
+	}{
+		{
+			name:     "output is correct with a separate destination",
+			dst:      Matrix{Rows: 2, Cols: 2, Data: make([]float64, 4)},
+			A:        Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			B:        Matrix{Rows: 2, Cols: 2, Data: []float64{0.5, -2, 6, 1}},
+			wantSum:  []float64{1.5, 0, 9, 5},
+			wantDiff: []float64{0.5, 4, -3, 3},
+			wantErr:  nil,
+		},
+		{
+			name:     "destination with wrong size fails",
+			dst:      Matrix{Rows: 1, Cols: 4, Data: make([]float64, 4)},
+			A:        Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			B:        Matrix{Rows: 2, Cols: 2, Data: []float64{0.5, -2, 6, 1}},
+			wantSum:  []float64{0, 0, 0, 0},
+			wantDiff: []float64{0, 0, 0, 0},
+			wantErr:  errIncorrectSize,
+		},
+		{
+			name:     "operands with different sizes fail",
+			dst:      Matrix{Rows: 2, Cols: 2, Data: make([]float64, 4)},
+			A:        Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			B:        Matrix{Rows: 4, Cols: 1, Data: []float64{0.5, -2, 6, 1}},
+			wantSum:  []float64{0, 0, 0, 0},
+			wantDiff: []float64{0, 0, 0, 0},
+			wantErr:  errIncorrectSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AdditionInto(tt.dst, tt.A, tt.B)
+			if err != tt.wantErr {
+				t.Errorf("AdditionInto(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			for i := range tt.wantSum {
+				if math.Abs(tt.dst.Data[i]-tt.wantSum[i]) > EPSILON {
+					t.Errorf("AdditionInto(): at index %d, got %f, want %f", i, tt.dst.Data[i], tt.wantSum[i])
+				}
+			}
+
+			err = SubractionInto(tt.dst, tt.A, tt.B)
+			if err != tt.wantErr {
+				t.Errorf("SubractionInto(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			for i := range tt.wantDiff {
+				if math.Abs(tt.dst.Data[i]-tt.wantDiff[i]) > EPSILON {
+					t.Errorf("SubractionInto(): at index %d, got %f, want %f", i, tt.dst.Data[i], tt.wantDiff[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMultiplicationInto(t *testing.T) {
+	A := createRandomMatrix(70, 80, 1)
+	B := createRandomMatrix(80, 90, 2)
+	want, _ := Multiplication(A, B)
+
+	tests := []struct {
+		name    string
+		dst     Matrix
+		A       Matrix
+		B       Matrix
+		want    Matrix
+		wantErr error
+	}{
+		{
+			name: "small matrices",
+			dst:  Matrix{Rows: 2, Cols: 2, Data: []float64{9, 9, 9, 9}},
+			A:    Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}},
+			B:    Matrix{Rows: 3, Cols: 2, Data: []float64{1, 0, 0, 1, 1, 1}},
+			want: Matrix{Rows: 2, Cols: 2, Data: []float64{4, 5, 10, 11}},
+		},
+		{
+			name: "old values of a large destination are overwritten",
+			dst:  createBigMatrix(70, 90, 5),
+			A:    A,
+			B:    B,
+			want: want,
+		},
+		{
+			name:    "destination with wrong size fails",
+			dst:     Matrix{Rows: 3, Cols: 2, Data: make([]float64, 6)},
+			A:       Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}},
+			B:       Matrix{Rows: 3, Cols: 2, Data: []float64{1, 0, 0, 1, 1, 1}},
+			want:    Matrix{Rows: 3, Cols: 2, Data: make([]float64, 6)},
+			wantErr: errIncorrectSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MultiplicationInto(tt.dst, tt.A, tt.B)
+			if err != tt.wantErr {
+				t.Errorf("MultiplicationInto(): returned incorrect error: %v, want %v", err, tt.wantErr)
+			}
+			for i := range tt.want.Data {
+				if tt.dst.Data[i] != tt.want.Data[i] {
+					t.Fatalf("MultiplicationInto(): at index %d, got %f, want %f", i, tt.dst.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTransposeAndScaleInto(t *testing.T) {
+	A := Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}}
+
+	dst := Matrix{Rows: 3, Cols: 2, Data: make([]float64, 6)}
+	if err := TransposeInto(dst, A); err != nil {
+		t.Fatalf("TransposeInto(): returned error: %v", err)
+	}
+	wantTranspose := []float64{1, 4, 2, 5, 3, 6}
+	for i := range wantTranspose {
+		if dst.Data[i] != wantTranspose[i] {
+			t.Errorf("TransposeInto(): at index %d, got %f, want %f", i, dst.Data[i], wantTranspose[i])
+		}
+	}
+	if err := TransposeInto(A, A); err != errIncorrectSize {
+		t.Errorf("TransposeInto(): returned incorrect error: %v, want %v", err, errIncorrectSize)
+	}
+
+	scaled := Matrix{Rows: 2, Cols: 3, Data: make([]float64, 6)}
+	if err := MultiplicationByScalarInto(scaled, A, -2); err != nil {
+		t.Fatalf("MultiplicationByScalarInto(): returned error: %v", err)
+	}
+	wantScaled := []float64{-2, -4, -6, -8, -10, -12}
+	for i := range wantScaled {
+		if scaled.Data[i] != wantScaled[i] {
+			t.Errorf("MultiplicationByScalarInto(): at index %d, got %f, want %f", i, scaled.Data[i], wantScaled[i])
+		}
+	}
+}
+
+func TestInPlace(t *testing.T) {
+	A := Matrix{Rows: 1, Cols: 3, Data: []float64{1, 2, 3}}
+	B := Matrix{Rows: 1, Cols: 3, Data: []float64{10, 20, 30}}
+
+	if err := AddInPlace(A, B); err != nil {
+		t.Fatalf("AddInPlace(): returned error: %v", err)
+	}
+	ScaleInPlace(A, 0.5)
+	if err := SubtractInPlace(A, B); err != nil {
+		t.Fatalf("SubtractInPlace(): returned error: %v", err)
+	}
+
+	want := []float64{-4.5, -9, -13.5}
+	for i := range want {
+		if math.Abs(A.Data[i]-want[i]) > EPSILON {
+			t.Errorf("InPlace: at index %d, got %f, want %f", i, A.Data[i], want[i])
+		}
+	}
+
+	if err := AddInPlace(A, Matrix{Rows: 3, Cols: 1, Data: []float64{1, 2, 3}}); err != errIncorrectSize {
+		t.Errorf("AddInPlace(): returned incorrect error: %v, want %v", err, errIncorrectSize)
+	}
+
+	// the in-place operations must not allocate
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = AddInPlace(A, B)
+		ScaleInPlace(A, 0.5)
+	})
+	if allocs != 0 {
+		t.Errorf("AddInPlace() and ScaleInPlace(): allocated %f times, want 0", allocs)
+	}
+}
@@ -5,12 +5,38 @@ import (
 	"sort"
 )
 
-type Matrix struct {
+// element types a matrix can hold
+type Float interface {
+	float32 | float64
+}
+
+// row-major matrix of float32 or float64 values
+type Dense[T Float] struct {
 	Rows int
 	Cols int
-	Data []float64
+	Data []T
+}
+
+// double precision matrix used by most of the program
+type Matrix = Dense[float64]
+
+// single precision matrix. Uses half the memory and bandwidth of Matrix
+type Matrix32 = Dense[float32]
+
+// returns the machine epsilon of the element type
+func Epsilon[T Float]() float64 {
+	if _, ok := any(T(0)).(float32); ok {
+		return 0x1p-23
+	}
+	return 0x1p-52
 }
 
+// matrices with fewer multiply-adds than this are multiplied with the simple loop
+const parallelThreshold = 64 * 64 * 64
+
+// size of the square blocks used by the blocked multiplication
+const blockSize = 64
+
 // define possible errors
 var (
 	errIncorrectSize = fmt.Errorf("incorrect size")
@@ -18,63 +44,96 @@ var (
 
 // multiplies all elements of given matrix by a scalar value
 // returns a new matrix containing the result
-func MultiplicationByScalar(A Matrix, scalar float64) Matrix {
-	result := Matrix{
+func MultiplicationByScalar[T Float](A Dense[T], scalar T) Dense[T] {
+	result := Dense[T]{
 		Rows: A.Rows,
 		Cols: A.Cols,
-		Data: make([]float64, A.Rows*A.Cols),
+		Data: make([]T, A.Rows*A.Cols),
 	}
 
-	for i, num := range A.Data {
-		result.Data[i] = num * scalar
-	}
+	// the sizes always match so the error can be ignored
+	_ = MultiplicationByScalarInto(result, A, scalar)
 
 	return result
 }
 
 // multiplies two matrices together
+// large matrices are multiplied in cache-sized blocks with the rows split between goroutines.
+// every element is still summed in the same order so the result is the same on every run
 // returns a new matrix containing the result or an error if dimensions are incompatible
-func Multiplication(A Matrix, B Matrix) (Matrix, error) {
+func Multiplication[T Float](A Dense[T], B Dense[T]) (Dense[T], error) {
 	if A.Cols != B.Rows {
-		return Matrix{}, errIncorrectSize
+		return Dense[T]{}, errIncorrectSize
 	}
 
-	result := Matrix{
+	result := Dense[T]{
 		Rows: A.Rows,
 		Cols: B.Cols,
-		Data: make([]float64, A.Rows*B.Cols),
+		Data: make([]T, A.Rows*B.Cols),
+	}
+
+	if err := MultiplicationInto(result, A, B); err != nil {
+		return Dense[T]{}, err
 	}
 
+	return result, nil
+}
+
+// computes result = A * B with a simple triple loop. Used for small matrices
+func multiplySimple[T Float](result, A, B Dense[T]) {
 	for n := range A.Rows {
 		rowOffset := n * A.Cols
 		resultRowOffset := n * B.Cols
 		for m := range B.Cols {
-			var sum float64 = 0
+			var sum T = 0
 			for k := range A.Cols {
 				sum += A.Data[rowOffset+k] * B.Data[k*B.Cols+m]
 			}
 			result.Data[resultRowOffset+m] = sum
 		}
 	}
+}
 
-	return result, nil
+// computes rows [rowStart, rowEnd) of result = A * B in blocks. The inner loop walks rows of B
+// and result contiguously instead of striding down the columns of B. The k blocks are processed
+// in ascending order so each element is summed in the same order as in multiplySimple
+func multiplyBlocked[T Float](result, A, B Dense[T], rowStart, rowEnd int) {
+	for ii := rowStart; ii < rowEnd; ii += blockSize {
+		iEnd := min(ii+blockSize, rowEnd)
+		for kk := 0; kk < A.Cols; kk += blockSize {
+			kEnd := min(kk+blockSize, A.Cols)
+			for jj := 0; jj < B.Cols; jj += blockSize {
+				jEnd := min(jj+blockSize, B.Cols)
+				for i := ii; i < iEnd; i++ {
+					resultRow := result.Data[i*B.Cols+jj : i*B.Cols+jEnd]
+					for k := kk; k < kEnd; k++ {
+						a := A.Data[i*A.Cols+k]
+						bRow := B.Data[k*B.Cols+jj : k*B.Cols+jEnd]
+						for j, b := range bRow {
+							resultRow[j] += a * b
+						}
+					}
+				}
+			}
+		}
+	}
 }
 
 // adds two matrices together
 // returns a new matrix containing the result or an error if dimensions are incompatible
-func Addition(A Matrix, B Matrix) (Matrix, error) {
+func Addition[T Float](A Dense[T], B Dense[T]) (Dense[T], error) {
 	if A.Rows != B.Rows || A.Cols != B.Cols {
-		return Matrix{}, errIncorrectSize
+		return Dense[T]{}, errIncorrectSize
 	}
 
-	result := Matrix{
+	result := Dense[T]{
 		Rows: A.Rows,
 		Cols: A.Cols,
-		Data: make([]float64, A.Rows*A.Cols),
+		Data: make([]T, A.Rows*A.Cols),
 	}
 
-	for n := range A.Data {
-		result.Data[n] = A.Data[n] + B.Data[n]
+	if err := AdditionInto(result, A, B); err != nil {
+		return Dense[T]{}, err
 	}
 
 	return result, nil
@@ -82,19 +141,19 @@ func Addition(A Matrix, B Matrix) (Matrix, error) {
 
 // Subracts two matrices
 // returns a new matrix containing the result or an error if dimensions are incompatible
-func Subraction(A Matrix, B Matrix) (Matrix, error) {
+func Subraction[T Float](A Dense[T], B Dense[T]) (Dense[T], error) {
 	if A.Rows != B.Rows || A.Cols != B.Cols {
-		return Matrix{}, errIncorrectSize
+		return Dense[T]{}, errIncorrectSize
 	}
 
-	result := Matrix{
+	result := Dense[T]{
 		Rows: A.Rows,
 		Cols: A.Cols,
-		Data: make([]float64, A.Rows*A.Cols),
+		Data: make([]T, A.Rows*A.Cols),
 	}
 
-	for n := range A.Data {
-		result.Data[n] = A.Data[n] - B.Data[n]
+	if err := SubractionInto(result, A, B); err != nil {
+		return Dense[T]{}, err
 	}
 
 	return result, nil
@@ -102,18 +161,15 @@ func Subraction(A Matrix, B Matrix) (Matrix, error) {
 
 // constructs by swapping rows and columns
 // returns a new matrix containign the result
-func Transpose(A Matrix) Matrix {
-	result := Matrix{
+func Transpose[T Float](A Dense[T]) Dense[T] {
+	result := Dense[T]{
 		Rows: A.Cols,
 		Cols: A.Rows,
-		Data: make([]float64, A.Rows*A.Cols),
+		Data: make([]T, A.Rows*A.Cols),
 	}
 
-	for n := range A.Rows {
-		for m := range A.Cols {
-			result.Data[m*A.Rows+n] = A.Data[n*A.Cols+m]
-		}
-	}
+	// the sizes always match so the error can be ignored
+	_ = TransposeInto(result, A)
 
 	return result
 }
@@ -121,10 +177,16 @@ func Transpose(A Matrix) Matrix {
 // constructs n * n identity matrix for given n value
 // returns a new matrix containing the result
 func Identity(n int) Matrix {
-	result := Matrix{
+	return IdentityOf[float64](n)
+}
+
+// constructs n * n identity matrix with the given element type
+// returns a new matrix containing the result
+func IdentityOf[T Float](n int) Dense[T] {
+	result := Dense[T]{
 		Rows: n,
 		Cols: n,
-		Data: make([]float64, n*n),
+		Data: make([]T, n*n),
 	}
 
 	for i := range n {
@@ -134,36 +196,45 @@ func Identity(n int) Matrix {
 	return result
 }
 
-// computes A * AT of a given matrix
-// returns a new matrix containing the result or an error
-// however, the error shouldn't be possible since the multiplication is always valid
-func Covariance(A Matrix) (Matrix, error) {
-	AT := Transpose(A)
+// converts the elements of a matrix to another element type
+// returns a new matrix containing the result
+func Convert[To, From Float](A Dense[From]) Dense[To] {
+	result := Dense[To]{
+		Rows: A.Rows,
+		Cols: A.Cols,
+		Data: make([]To, len(A.Data)),
+	}
 
-	result, err := Multiplication(AT, A)
-	if err != nil {
-		return Matrix{}, err
+	for i, num := range A.Data {
+		result.Data[i] = To(num)
 	}
 
-	return result, nil
+	return result
+}
+
+// computes AT * A of a given matrix
+// returns a new matrix containing the result or an error
+// however, the error shouldn't be possible since the multiplication is always valid
+func Covariance[T Float](A Dense[T]) (Dense[T], error) {
+	return Gram(A), nil
 }
 
 // computes the difference between each input matrix and the mean matrix
 // returns a new matrix where each column represents the difference vector for a corresponding input
-func DifferenceMatrix(vectors []Matrix, mean Matrix) (Matrix, error) {
-	result := Matrix{
+func DifferenceMatrix[T Float](vectors []Dense[T], mean Dense[T]) (Dense[T], error) {
+	result := Dense[T]{
 		Rows: vectors[0].Rows,
 		Cols: len(vectors),
-		Data: make([]float64, vectors[0].Rows*len(vectors)),
+		Data: make([]T, vectors[0].Rows*len(vectors)),
 	}
 
 	for i := range vectors {
-		diff, err := Subraction(vectors[i], mean)
-		if err != nil {
-			return Matrix{}, err
+		if vectors[i].Rows != mean.Rows || vectors[i].Cols != mean.Cols {
+			return Dense[T]{}, errIncorrectSize
 		}
+		col := result.Col(i)
 		for j := range vectors[i].Rows {
-			result.Data[j*result.Cols+i] = diff.Data[j]
+			col.Set(j, 0, vectors[i].Data[j]-mean.Data[j])
 		}
 	}
 
@@ -171,28 +242,63 @@ func DifferenceMatrix(vectors []Matrix, mean Matrix) (Matrix, error) {
 }
 
 // sorts the eigenvalues and eigenvectors in descending order and rearranges the columns
-// of the eigenvectors matrix accordingly. Returns sorted eigenvectors
-func SortEigenvectors(eigenvalues []float64, eigenvectors Matrix) Matrix {
+// of the eigenvectors matrix accordingly. Equal eigenvalues keep their original order
+// Returns sorted eigenvectors
+func SortEigenvectors[T Float](eigenvalues []T, eigenvectors Dense[T]) Dense[T] {
 	indices := make([]int, len(eigenvalues))
 	for i := range indices {
 		indices[i] = i
 	}
 
-	sort.Slice(indices, func(i, j int) bool {
+	sort.SliceStable(indices, func(i, j int) bool {
 		return eigenvalues[indices[i]] > eigenvalues[indices[j]]
 	})
 
-	result := Matrix{
+	result := Dense[T]{
 		Rows: eigenvectors.Rows,
 		Cols: eigenvectors.Cols,
-		Data: make([]float64, len(eigenvectors.Data)),
+		Data: make([]T, len(eigenvectors.Data)),
 	}
 
 	for newCol, idx := range indices {
-		for row := range eigenvectors.Rows {
-			result.Data[row*eigenvectors.Cols+newCol] = eigenvectors.Data[row*eigenvectors.Cols+idx]
-		}
+		// the column sizes always match so the error can be ignored
+		_ = result.SetCol(newCol, eigenvectors.Col(idx))
 	}
 
 	return result
 }
+
+// relative difference below which two elements count as equally large in CanonicalSigns
+const signTolerance = 1e-6
+
+// flips the columns of vectors in place so that the element with the largest absolute value
+// of every column is positive. An eigenvector or singular vector is only defined up to its sign,
+// and this rule picks the same sign for it regardless of the solver or the machine. Elements
+// within signTolerance of the largest one are ties and the first of them decides, so rounding
+// differences in the last bits don't flip the sign
+// returns for each column whether it was flipped
+func CanonicalSigns[T Float](vectors Dense[T]) []bool {
+	flipped := make([]bool, vectors.Cols)
+	for j := range vectors.Cols {
+		col := vectors.Col(j)
+
+		var largest T
+		for i := range col.Rows {
+			largest = max(largest, Abs(col.At(i, 0)))
+		}
+		for i := range col.Rows {
+			value := col.At(i, 0)
+			if Abs(value) >= largest*(1-signTolerance) {
+				flipped[j] = value < 0
+				break
+			}
+		}
+
+		if flipped[j] {
+			for i := range col.Rows {
+				col.Set(i, 0, -col.At(i, 0))
+			}
+		}
+	}
+	return flipped
+}
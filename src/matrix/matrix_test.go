@@ -2,6 +2,7 @@ package matrix
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -219,6 +220,67 @@ func TestMultiplication(t *testing.T) {
 	}
 }
 
+func createRandomMatrix(rows int, cols int, seed int64) Matrix {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, rows*cols)
+	for i := range data {
+		data[i] = rng.Float64()*200 - 100
+	}
+	return Matrix{
+		Rows: rows,
+		Cols: cols,
+		Data: data,
+	}
+}
+
+func TestMultiplicationBlocked(t *testing.T) {
+	tests := []struct {
+		name string
+		A    Matrix
+		B    Matrix
+	}{
+		{
+			name: "sizes that are not multiples of the block size",
+			A:    createRandomMatrix(130, 70, 1),
+			B:    createRandomMatrix(70, 150, 2),
+		},
+		{
+			name: "long inner dimension like the covariance of image vectors",
+			A:    createRandomMatrix(20, 10304, 3),
+			B:    createRandomMatrix(10304, 20, 4),
+		},
+		{
+			name: "outer product",
+			A:    createRandomMatrix(300, 1, 5),
+			B:    createRandomMatrix(1, 300, 6),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := Matrix{
+				Rows: tt.A.Rows,
+				Cols: tt.B.Cols,
+				Data: make([]float64, tt.A.Rows*tt.B.Cols),
+			}
+			multiplySimple(want, tt.A, tt.B)
+
+			// the result must be bit-for-bit the same as the simple loop on every run
+			for range 3 {
+				result, err := Multiplication(tt.A, tt.B)
+				if err != nil {
+					t.Fatalf("Multiplication(): returned error: %v", err)
+				}
+				for i := range want.Data {
+					if result.Data[i] != want.Data[i] {
+						t.Fatalf("Multiplication(): at index %d, got %v, want %v", i, result.Data[i], want.Data[i])
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestAddition(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -568,6 +630,34 @@ func TestDifferenceMatrix(t *testing.T) {
 	}
 }
 
+func TestConvert(t *testing.T) {
+	A := Matrix{Rows: 2, Cols: 2, Data: []float64{1.5, -2, 1e-3, 255}}
+
+	single := Convert[float32](A)
+	if single.Rows != A.Rows || single.Cols != A.Cols {
+		t.Fatalf("Convert(): got %dx%d matrix, want %dx%d", single.Rows, single.Cols, A.Rows, A.Cols)
+	}
+
+	back := Convert[float64](single)
+	for i := range A.Data {
+		if math.Abs(back.Data[i]-A.Data[i]) > EPSILON {
+			t.Errorf("Convert(): at index %d, got %f, want %f", i, back.Data[i], A.Data[i])
+		}
+	}
+
+	// the float32 operations must agree with float64 up to single precision
+	want, _ := Multiplication(createRandomMatrix(80, 70, 1), createRandomMatrix(70, 90, 2))
+	result, err := Multiplication(Convert[float32](createRandomMatrix(80, 70, 1)), Convert[float32](createRandomMatrix(70, 90, 2)))
+	if err != nil {
+		t.Fatalf("Multiplication(): returned error %v", err)
+	}
+	for i := range want.Data {
+		if math.Abs(float64(result.Data[i])-want.Data[i]) > 1e-5*70*100*100 {
+			t.Fatalf("Multiplication(): float32 at index %d, got %f, want %f", i, result.Data[i], want.Data[i])
+		}
+	}
+}
+
 func TestCovariance(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -664,6 +754,21 @@ func TestSortEigenvectors(t *testing.T) {
 				Data: []float64{0.25, 0.5, 1, 0.10, 8, 2, 0.2, 0, 1.01, 0.99, 0.1, 1, 10.1, 1, 0, 9.9999},
 			},
 		},
+		{
+			name:   "equal eigenvalues keep their order",
+			values: []float64{1, 3, 1, 3},
+			vectors: Matrix{
+				Rows: 2,
+				Cols: 4,
+				Data: []float64{1, 2, 3, 4, 5, 6, 7, 8},
+			},
+			wantValues: []float64{3, 3, 1, 1},
+			wantVectors: Matrix{
+				Rows: 2,
+				Cols: 4,
+				Data: []float64{2, 4, 1, 3, 6, 8, 5, 7},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -685,3 +790,47 @@ func TestSortEigenvectors(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalSigns(t *testing.T) {
+	tests := []struct {
+		name        string
+		vectors     Matrix
+		wantVectors Matrix
+		wantFlipped []bool
+	}{
+		{
+			name:        "largest element decides the sign",
+			vectors:     Matrix{Rows: 3, Cols: 2, Data: []float64{0.6, -0.1, -0.8, 0.2, 0, 0.9}},
+			wantVectors: Matrix{Rows: 3, Cols: 2, Data: []float64{-0.6, -0.1, 0.8, 0.2, 0, 0.9}},
+			wantFlipped: []bool{true, false},
+		},
+		{
+			name:        "first of equally large elements decides the sign",
+			vectors:     Matrix{Rows: 2, Cols: 2, Data: []float64{-0.70710678, 0.70710678, 0.70710679, 0.70710678}},
+			wantVectors: Matrix{Rows: 2, Cols: 2, Data: []float64{0.70710678, 0.70710678, -0.70710679, 0.70710678}},
+			wantFlipped: []bool{true, false},
+		},
+		{
+			name:        "zero vector is left unchanged",
+			vectors:     Matrix{Rows: 2, Cols: 1, Data: []float64{0, 0}},
+			wantVectors: Matrix{Rows: 2, Cols: 1, Data: []float64{0, 0}},
+			wantFlipped: []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flipped := CanonicalSigns(tt.vectors)
+			for i := range tt.wantFlipped {
+				if flipped[i] != tt.wantFlipped[i] {
+					t.Errorf("CanonicalSigns(): column %d flipped %v, want %v", i, flipped[i], tt.wantFlipped[i])
+				}
+			}
+			for i := range tt.wantVectors.Data {
+				if tt.vectors.Data[i] != tt.wantVectors.Data[i] {
+					t.Errorf("CanonicalSigns(): at index %d, got %f, want %f", i, tt.vectors.Data[i], tt.wantVectors.Data[i])
+				}
+			}
+		})
+	}
+}
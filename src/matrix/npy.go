@@ -0,0 +1,256 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// the .npy format stores a magic string, a version, the length of a header and a
+// python dict literal describing the array, followed by the raw elements.
+// see https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+const npyMagic = "\x93NUMPY"
+
+// headers longer than this are rejected like NumPy does, so a corrupted length can't allocate gigabytes
+const npyMaxHeaderLength = 10000
+
+var (
+	npyDescr   = regexp.MustCompile(`'descr':\s*'([<>|=]?)([a-z])(\d+)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order':\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape':\s*\(([^)]*)\)`)
+)
+
+// writes the matrix in NumPy .npy format as a 2D little-endian float64 or float32 array
+// returns an error if writing fails
+func WriteNpy[T Float](w io.Writer, A Dense[T]) error {
+	size := elementSize[T]()
+	header := fmt.Sprintf("{'descr': '<f%d', 'fortran_order': False, 'shape': (%d, %d), }", size, A.Rows, A.Cols)
+
+	// the header is padded with spaces and a newline so the data starts at a multiple of 64 bytes
+	prefix := len(npyMagic) + 4
+	padding := 64 - (prefix+len(header)+1)%64
+	if padding == 64 {
+		padding = 0
+	}
+	header += strings.Repeat(" ", padding) + "\n"
+
+	data := make([]byte, prefix+len(header)+size*len(A.Data))
+	copy(data, npyMagic)
+	data[6] = 1 // version 1.0
+	binary.LittleEndian.PutUint16(data[8:], uint16(len(header)))
+	copy(data[prefix:], header)
+	putElements(data[prefix+len(header):], A.Data, size)
+
+	_, err := w.Write(data)
+	return err
+}
+
+// reads a NumPy .npy array of float64 or float32 values. A 1D array of length n becomes
+// an n x 1 column vector and Fortran ordered arrays are converted to row-major order
+// returns the matrix or an error if the file isn't a supported array
+func ReadNpy[T Float](r io.Reader) (Dense[T], error) {
+	return readNpy[T](r, math.MaxInt64)
+}
+
+// reads a NumPy .npy array whose element data is at most limit bytes
+// returns the matrix or an error if the file isn't a supported array or its data is too large
+func readNpy[T Float](r io.Reader, limit int64) (Dense[T], error) {
+	prefix := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil || string(prefix[:len(npyMagic)]) != npyMagic {
+		return Dense[T]{}, errInvalidFormat
+	}
+
+	var headerLength int
+	switch prefix[6] {
+	case 1:
+		var length uint16
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return Dense[T]{}, errInvalidFormat
+		}
+		headerLength = int(length)
+	case 2, 3:
+		var length uint32
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return Dense[T]{}, errInvalidFormat
+		}
+		headerLength = int(length)
+	default:
+		return Dense[T]{}, errInvalidFormat
+	}
+	if headerLength > npyMaxHeaderLength {
+		return Dense[T]{}, errInvalidFormat
+	}
+
+	header := make([]byte, headerLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Dense[T]{}, errInvalidFormat
+	}
+
+	descr := npyDescr.FindSubmatch(header)
+	fortran := npyFortran.FindSubmatch(header)
+	shape := npyShape.FindSubmatch(header)
+	if descr == nil || fortran == nil || shape == nil {
+		return Dense[T]{}, errInvalidFormat
+	}
+	if string(descr[1]) == ">" || string(descr[2]) != "f" {
+		return Dense[T]{}, errUnsupportedType
+	}
+	size, _ := strconv.Atoi(string(descr[3]))
+	if size != 4 && size != 8 {
+		return Dense[T]{}, errUnsupportedType
+	}
+
+	var dims []int
+	for _, field := range strings.Split(string(shape[1]), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		dim, err := strconv.Atoi(field)
+		if err != nil || dim < 0 {
+			return Dense[T]{}, errInvalidFormat
+		}
+		dims = append(dims, dim)
+	}
+
+	result := Dense[T]{}
+	switch len(dims) {
+	case 1:
+		result.Rows, result.Cols = dims[0], 1
+	case 2:
+		result.Rows, result.Cols = dims[0], dims[1]
+	default:
+		return Dense[T]{}, errInvalidFormat
+	}
+
+	// the shape comes from the file so it is bounded before multiplying it to avoid an overflow
+	if result.Rows != 0 && result.Cols > math.MaxInt/size/result.Rows {
+		return Dense[T]{}, errInvalidFormat
+	}
+	length := int64(size * result.Rows * result.Cols)
+	if length > limit {
+		return Dense[T]{}, errInvalidFormat
+	}
+	// the buffer grows while the data is read, so a shape that is larger than the file fails
+	// when the data runs out instead of allocating the whole claimed size up front
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, r, length); err != nil {
+		return Dense[T]{}, errInvalidFormat
+	}
+	raw := buffer.Bytes()
+	result.Data = make([]T, result.Rows*result.Cols)
+	getElements(result.Data, raw, size)
+
+	if string(fortran[1]) == "True" && len(dims) == 2 {
+		// column-major data reads as the transpose
+		stored := Dense[T]{Rows: result.Cols, Cols: result.Rows, Data: result.Data}
+		result = Transpose(stored)
+	}
+
+	return result, nil
+}
+
+// writes the matrices into a NumPy .npz archive readable with numpy.load
+// each matrix is stored as name.npy in the order of the sorted names
+// returns an error if writing fails
+func WriteNpz[T Float](w io.Writer, arrays map[string]Dense[T]) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		file, err := archive.Create(name + ".npy")
+		if err != nil {
+			return err
+		}
+		if err := WriteNpy(file, arrays[name]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// reads all arrays of a NumPy .npz archive. The names are the file names without .npy
+// returns the matrices by name or an error if the archive or an array is invalid
+func ReadNpz[T Float](r io.ReaderAt, size int64) (map[string]Dense[T], error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errInvalidFormat
+	}
+
+	arrays := make(map[string]Dense[T], len(archive.File))
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			return nil, errInvalidFormat
+		}
+		// the data of an entry can't be larger than the entry itself
+		array, err := readNpy[T](reader, int64(min(file.UncompressedSize64, math.MaxInt64)))
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		arrays[strings.TrimSuffix(file.Name, ".npy")] = array
+	}
+
+	return arrays, nil
+}
+
+// unit tests ignored since I/O testing wasn't required
+// saves the matrix into a .npy file
+// returns an error if the file can't be written
+func SaveNpy[T Float](path string, A Dense[T]) error {
+	var buffer bytes.Buffer
+	if err := WriteNpy(&buffer, A); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buffer.Bytes(), 0o644)
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads a matrix from a .npy file
+// returns the matrix or an error if the file can't be read
+func LoadNpy[T Float](path string) (Dense[T], error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+	defer file.Close()
+
+	return ReadNpy[T](file)
+}
+
+// unit tests ignored since I/O testing wasn't required
+// saves the matrices into a .npz file
+// returns an error if the file can't be written
+func SaveNpz[T Float](path string, arrays map[string]Dense[T]) error {
+	var buffer bytes.Buffer
+	if err := WriteNpz(&buffer, arrays); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buffer.Bytes(), 0o644)
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads all matrices from a .npz file
+// returns the matrices by name or an error if the file can't be read
+func LoadNpz[T Float](path string) (map[string]Dense[T], error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadNpz[T](bytes.NewReader(data), int64(len(data)))
+}
@@ -0,0 +1,147 @@
+package matrix
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// define possible errors
+var (
+	errInvalidFormat   = fmt.Errorf("invalid matrix data")
+	errUnsupportedType = fmt.Errorf("unsupported element type")
+)
+
+// binary format: magic, element size in bytes, rows and cols as uint32 and the elements
+// in row-major order, all little-endian
+var binaryMagic = [4]byte{'M', 'A', 'T', '1'}
+
+const binaryHeaderSize = 13
+
+// returns the size of the element type in bytes
+func elementSize[T Float]() int {
+	if _, ok := any(T(0)).(float32); ok {
+		return 4
+	}
+	return 8
+}
+
+// encodes the matrix in a little-endian binary format. Implements encoding.BinaryMarshaler
+// returns the encoded matrix
+func (A Dense[T]) MarshalBinary() ([]byte, error) {
+	size := elementSize[T]()
+	data := make([]byte, binaryHeaderSize+size*len(A.Data))
+	copy(data, binaryMagic[:])
+	data[4] = byte(size)
+	binary.LittleEndian.PutUint32(data[5:], uint32(A.Rows))
+	binary.LittleEndian.PutUint32(data[9:], uint32(A.Cols))
+
+	putElements(data[binaryHeaderSize:], A.Data, size)
+
+	return data, nil
+}
+
+// decodes a matrix encoded with MarshalBinary. Data of either element type is converted
+// to the element type of the matrix. Implements encoding.BinaryUnmarshaler
+// returns an error if the data is invalid
+func (A *Dense[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < binaryHeaderSize || [4]byte(data[:4]) != binaryMagic {
+		return errInvalidFormat
+	}
+
+	size := int(data[4])
+	if size != 4 && size != 8 {
+		return errUnsupportedType
+	}
+	rows := int(binary.LittleEndian.Uint32(data[5:]))
+	cols := int(binary.LittleEndian.Uint32(data[9:]))
+	// the dimensions come from the data so they are bounded before multiplying them to avoid an overflow
+	if rows != 0 && cols > (len(data)-binaryHeaderSize)/size/rows {
+		return errInvalidFormat
+	}
+	if len(data)-binaryHeaderSize != rows*cols*size {
+		return errInvalidFormat
+	}
+
+	A.Rows = rows
+	A.Cols = cols
+	A.Data = make([]T, rows*cols)
+	getElements(A.Data, data[binaryHeaderSize:], size)
+
+	return nil
+}
+
+// writes the elements as little-endian floats of the given size
+func putElements[T Float](dst []byte, values []T, size int) {
+	for i, num := range values {
+		if size == 4 {
+			binary.LittleEndian.PutUint32(dst[i*4:], math.Float32bits(float32(num)))
+		} else {
+			binary.LittleEndian.PutUint64(dst[i*8:], math.Float64bits(float64(num)))
+		}
+	}
+}
+
+// reads little-endian floats of the given size into values
+func getElements[T Float](values []T, src []byte, size int) {
+	for i := range values {
+		if size == 4 {
+			values[i] = T(math.Float32frombits(binary.LittleEndian.Uint32(src[i*4:])))
+		} else {
+			values[i] = T(math.Float64frombits(binary.LittleEndian.Uint64(src[i*8:])))
+		}
+	}
+}
+
+// writes the matrix as comma separated values with one line per row
+// the values are written with as many digits as needed to read back the same numbers
+// returns an error if writing fails
+func WriteCSV[T Float](w io.Writer, A Dense[T]) error {
+	writer := csv.NewWriter(w)
+	bits := elementSize[T]() * 8
+	record := make([]string, A.Cols)
+	for i := range A.Rows {
+		for j, num := range A.Row(i) {
+			record[j] = strconv.FormatFloat(float64(num), 'g', -1, bits)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// reads a matrix written as comma separated values with one line per row
+// returns the matrix or an error if the rows have different lengths or a value isn't a number
+func ReadCSV[T Float](r io.Reader) (Dense[T], error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Dense[T]{}, errInvalidFormat
+	}
+
+	result := Dense[T]{Rows: len(records)}
+	if len(records) > 0 {
+		result.Cols = len(records[0])
+	}
+	result.Data = make([]T, 0, result.Rows*result.Cols)
+	bits := elementSize[T]() * 8
+
+	for _, record := range records {
+		for _, field := range record {
+			num, err := strconv.ParseFloat(field, bits)
+			if err != nil {
+				return Dense[T]{}, errInvalidFormat
+			}
+			result.Data = append(result.Data, T(num))
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,316 @@
+package matrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	A := Matrix{Rows: 2, Cols: 3, Data: []float64{1.5, -2, math.Pi, 0, 1e-300, 255}}
+
+	data, err := A.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(): returned error %v", err)
+	}
+
+	var result Matrix
+	if err := result.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(): returned error %v", err)
+	}
+	if result.Rows != A.Rows || result.Cols != A.Cols {
+		t.Fatalf("UnmarshalBinary(): got %dx%d matrix, want %dx%d", result.Rows, result.Cols, A.Rows, A.Cols)
+	}
+	for i := range A.Data {
+		if result.Data[i] != A.Data[i] {
+			t.Errorf("UnmarshalBinary(): at index %d, got %g, want %g", i, result.Data[i], A.Data[i])
+		}
+	}
+
+	// float64 data can be read into a float32 matrix
+	var single Matrix32
+	if err := single.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(): float32 returned error %v", err)
+	}
+	if single.Data[0] != 1.5 || single.Data[5] != 255 {
+		t.Errorf("UnmarshalBinary(): float32 got %v", single.Data)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "too short data fails",
+			data:    data[:5],
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "wrong magic fails",
+			data:    append([]byte("XXXX"), data[4:]...),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "missing elements fail",
+			data:    data[:len(data)-8],
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "dimensions whose product overflows fail",
+			data:    append(append([]byte{}, data[:5]...), 0, 0, 0, 0x80, 0, 0, 0, 0x80),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "unknown element size fails",
+			data:    append(append([]byte{}, data[:4]...), append([]byte{2}, data[5:]...)...),
+			wantErr: errUnsupportedType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result Matrix
+			if err := result.UnmarshalBinary(tt.data); err != tt.wantErr {
+				t.Errorf("UnmarshalBinary(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSV(t *testing.T) {
+	A := Matrix{Rows: 2, Cols: 2, Data: []float64{0.1, -2, 1e20, 3}}
+
+	var buffer bytes.Buffer
+	if err := WriteCSV(&buffer, A); err != nil {
+		t.Fatalf("WriteCSV(): returned error %v", err)
+	}
+	if buffer.String() != "0.1,-2\n1e+20,3\n" {
+		t.Errorf("WriteCSV(): got %q", buffer.String())
+	}
+
+	result, err := ReadCSV[float64](&buffer)
+	if err != nil {
+		t.Fatalf("ReadCSV(): returned error %v", err)
+	}
+	if result.Rows != 2 || result.Cols != 2 {
+		t.Fatalf("ReadCSV(): got %dx%d matrix, want 2x2", result.Rows, result.Cols)
+	}
+	for i := range A.Data {
+		if result.Data[i] != A.Data[i] {
+			t.Errorf("ReadCSV(): at index %d, got %g, want %g", i, result.Data[i], A.Data[i])
+		}
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:    "spaces after commas are allowed",
+			input:   "1, 2\n3, 4\n",
+			wantErr: nil,
+		},
+		{
+			name:    "rows with different lengths fail",
+			input:   "1,2\n3\n",
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "values that aren't numbers fail",
+			input:   "1,x\n",
+			wantErr: errInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadCSV[float64](strings.NewReader(tt.input)); err != tt.wantErr {
+				t.Errorf("ReadCSV(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// builds a .npy file the way NumPy writes it
+func npyFile(version byte, header string, values []float64, size int) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(npyMagic)
+	buffer.Write([]byte{version, 0})
+	if version == 1 {
+		binary.Write(&buffer, binary.LittleEndian, uint16(len(header)))
+	} else {
+		binary.Write(&buffer, binary.LittleEndian, uint32(len(header)))
+	}
+	buffer.WriteString(header)
+	data := make([]byte, size*len(values))
+	putElements(data, values, size)
+	buffer.Write(data)
+	return buffer.Bytes()
+}
+
+func TestNpy(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Matrix
+		wantErr error
+	}{
+		{
+			name: "2D float64 array",
+			data: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }\n", []float64{1, 2, 3, 4, 5, 6}, 8),
+			want: Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}},
+		},
+		{
+			name: "1D float32 array is a column vector",
+			data: npyFile(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (3,), }\n", []float64{0.5, 1, 2}, 4),
+			want: Matrix{Rows: 3, Cols: 1, Data: []float64{0.5, 1, 2}},
+		},
+		{
+			name: "fortran ordered array",
+			data: npyFile(1, "{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3), }\n", []float64{1, 4, 2, 5, 3, 6}, 8),
+			want: Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}},
+		},
+		{
+			name: "version 2 header",
+			data: npyFile(2, "{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2), }\n", []float64{7, 8}, 8),
+			want: Matrix{Rows: 1, Cols: 2, Data: []float64{7, 8}},
+		},
+		{
+			name:    "integer array fails",
+			data:    npyFile(1, "{'descr': '<i8', 'fortran_order': False, 'shape': (2,), }\n", []float64{1, 2}, 8),
+			wantErr: errUnsupportedType,
+		},
+		{
+			name:    "big-endian array fails",
+			data:    npyFile(1, "{'descr': '>f8', 'fortran_order': False, 'shape': (2,), }\n", []float64{1, 2}, 8),
+			wantErr: errUnsupportedType,
+		},
+		{
+			name:    "3D array fails",
+			data:    npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 2), }\n", []float64{1, 2}, 8),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "shape whose size overflows fails",
+			data:    npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (4294967296, 4294967296), }\n", []float64{}, 8),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "shape larger than the file fails",
+			data:    npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (100000, 100000), }\n", []float64{1, 2}, 8),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "header longer than the limit fails",
+			data:    append(npyFile(2, "{'descr': '<f8', 'fortran_order': False, 'shape': (1,), }\n", []float64{}, 8)[:8], 0xff, 0xff, 0xff, 0xff),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "truncated data fails",
+			data:    npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }\n", []float64{1, 2}, 8),
+			wantErr: errInvalidFormat,
+		},
+		{
+			name:    "wrong magic fails",
+			data:    []byte("not a numpy file"),
+			wantErr: errInvalidFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ReadNpy[float64](bytes.NewReader(tt.data))
+			if err != tt.wantErr {
+				t.Fatalf("ReadNpy(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if result.Rows != tt.want.Rows || result.Cols != tt.want.Cols {
+				t.Fatalf("ReadNpy(): got %dx%d matrix, want %dx%d", result.Rows, result.Cols, tt.want.Rows, tt.want.Cols)
+			}
+			for i := range tt.want.Data {
+				if result.Data[i] != tt.want.Data[i] {
+					t.Errorf("ReadNpy(): at index %d, got %g, want %g", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteNpy(t *testing.T) {
+	A := createRandomMatrix(3, 5, 1)
+
+	var buffer bytes.Buffer
+	if err := WriteNpy(&buffer, A); err != nil {
+		t.Fatalf("WriteNpy(): returned error %v", err)
+	}
+
+	// NumPy requires the data to start at a multiple of 64 bytes
+	data := buffer.Bytes()
+	headerLength := int(binary.LittleEndian.Uint16(data[8:]))
+	if (10+headerLength)%64 != 0 || data[10+headerLength-1] != '\n' {
+		t.Errorf("WriteNpy(): header of length %d isn't padded to 64 bytes", headerLength)
+	}
+
+	result, err := ReadNpy[float64](&buffer)
+	if err != nil {
+		t.Fatalf("ReadNpy(): returned error %v", err)
+	}
+	compareExactly(t, "WriteNpy()", result, A)
+
+	buffer.Reset()
+	if err := WriteNpy(&buffer, Convert[float32](A)); err != nil {
+		t.Fatalf("WriteNpy(): float32 returned error %v", err)
+	}
+	if !strings.Contains(buffer.String(), "'descr': '<f4'") {
+		t.Errorf("WriteNpy(): float32 matrix wasn't written as <f4")
+	}
+}
+
+func TestNpz(t *testing.T) {
+	arrays := map[string]Matrix{
+		"eigenfaces": createRandomMatrix(6, 2, 1),
+		"mean":       createRandomMatrix(6, 1, 2),
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteNpz(&buffer, arrays); err != nil {
+		t.Fatalf("WriteNpz(): returned error %v", err)
+	}
+
+	result, err := ReadNpz[float64](bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("ReadNpz(): returned error %v", err)
+	}
+	if len(result) != len(arrays) {
+		t.Fatalf("ReadNpz(): got %d arrays, want %d", len(result), len(arrays))
+	}
+	for name, want := range arrays {
+		compareExactly(t, "ReadNpz() "+name, result[name], want)
+	}
+
+	// an entry whose shape claims more data than the entry holds fails before reading it
+	buffer.Reset()
+	archive := zip.NewWriter(&buffer)
+	file, err := archive.Create("huge.npy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write(npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (100000, 100000), }\n", []float64{1, 2}, 8))
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadNpz[float64](bytes.NewReader(buffer.Bytes()), int64(buffer.Len())); err != errInvalidFormat {
+		t.Errorf("ReadNpz(): returned wrong error %v for a shape larger than the entry, want %v", err, errInvalidFormat)
+	}
+
+	if _, err := ReadNpz[float64](strings.NewReader("not a zip"), 9); err != errInvalidFormat {
+		t.Errorf("ReadNpz(): returned wrong error %v, want %v", err, errInvalidFormat)
+	}
+}
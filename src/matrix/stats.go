@@ -0,0 +1,348 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// define possible errors
+var (
+	errNotEnoughSamples = fmt.Errorf("not enough samples")
+	errWrongLabelCount  = fmt.Errorf("number of labels doesn't match the number of columns")
+)
+
+// the statistics in this file follow the layout of DifferenceMatrix: every column is a sample
+// and every row a feature. ddof is subtracted from the sample count when dividing, so 0 gives
+// the population variance and 1 the unbiased sample variance
+
+// computes the mean of each row
+// returns a Rows x 1 column vector
+func RowMeans[T Float](A Dense[T]) Dense[T] {
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: 1,
+		Data: make([]T, A.Rows),
+	}
+	if A.Cols == 0 {
+		return result
+	}
+
+	for i := range A.Rows {
+		var sum T
+		for _, num := range A.Row(i) {
+			sum += num
+		}
+		result.Data[i] = sum / T(A.Cols)
+	}
+
+	return result
+}
+
+// computes the mean of each column
+// returns a 1 x Cols row vector
+func ColMeans[T Float](A Dense[T]) Dense[T] {
+	result := Dense[T]{
+		Rows: 1,
+		Cols: A.Cols,
+		Data: make([]T, A.Cols),
+	}
+	if A.Rows == 0 {
+		return result
+	}
+
+	for i := range A.Rows {
+		Axpy(1, A.Row(i), result.Data)
+	}
+	ScaleInPlace(result, 1/T(A.Rows))
+
+	return result
+}
+
+// computes the variance of each row with two passes over the data
+// returns a Rows x 1 column vector or an error if there are no more columns than ddof
+func RowVariances[T Float](A Dense[T], ddof int) (Dense[T], error) {
+	if A.Cols-ddof <= 0 {
+		return Dense[T]{}, errNotEnoughSamples
+	}
+
+	means := RowMeans(A)
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: 1,
+		Data: make([]T, A.Rows),
+	}
+	for i := range A.Rows {
+		var sum T
+		for _, num := range A.Row(i) {
+			diff := num - means.Data[i]
+			sum += diff * diff
+		}
+		result.Data[i] = sum / T(A.Cols-ddof)
+	}
+
+	return result, nil
+}
+
+// computes the variance of each column with two passes over the data
+// returns a 1 x Cols row vector or an error if there are no more rows than ddof
+func ColVariances[T Float](A Dense[T], ddof int) (Dense[T], error) {
+	if A.Rows-ddof <= 0 {
+		return Dense[T]{}, errNotEnoughSamples
+	}
+
+	means := ColMeans(A)
+	result := Dense[T]{
+		Rows: 1,
+		Cols: A.Cols,
+		Data: make([]T, A.Cols),
+	}
+	for i := range A.Rows {
+		for j, num := range A.Row(i) {
+			diff := num - means.Data[j]
+			result.Data[j] += diff * diff
+		}
+	}
+	ScaleInPlace(result, 1/T(A.Rows-ddof))
+
+	return result, nil
+}
+
+// computes the standard deviation of each row
+// returns a Rows x 1 column vector or an error if there are no more columns than ddof
+func RowStdDevs[T Float](A Dense[T], ddof int) (Dense[T], error) {
+	result, err := RowVariances(A, ddof)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+	sqrtInPlace(result.Data)
+
+	return result, nil
+}
+
+// computes the standard deviation of each column
+// returns a 1 x Cols row vector or an error if there are no more rows than ddof
+func ColStdDevs[T Float](A Dense[T], ddof int) (Dense[T], error) {
+	result, err := ColVariances(A, ddof)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+	sqrtInPlace(result.Data)
+
+	return result, nil
+}
+
+// replaces every value with its square root
+func sqrtInPlace[T Float](values []T) {
+	for i, num := range values {
+		values[i] = T(math.Sqrt(float64(num)))
+	}
+}
+
+// standardizes every row (feature) to zero mean and unit population standard deviation
+// over the columns (samples). Rows with zero deviation are set to zero
+// returns the z-scores and the row means and standard deviations needed to standardize
+// new samples, or an error if the matrix has no columns
+func Standardize[T Float](A Dense[T]) (Dense[T], Dense[T], Dense[T], error) {
+	stds, err := RowStdDevs(A, 0)
+	if err != nil {
+		return Dense[T]{}, Dense[T]{}, Dense[T]{}, err
+	}
+	means := RowMeans(A)
+
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: A.Cols,
+		Data: make([]T, len(A.Data)),
+	}
+	for i := range A.Rows {
+		if stds.Data[i] == 0 {
+			continue
+		}
+		row := result.Row(i)
+		for j, num := range A.Row(i) {
+			row[j] = (num - means.Data[i]) / stds.Data[i]
+		}
+	}
+
+	return result, means, stds, nil
+}
+
+// groups the column indices by label
+// returns the sorted labels and the columns of each label or an error if the label count is wrong
+func groupColumns[T Float](A Dense[T], labels []int) ([]int, map[int][]int, error) {
+	if len(labels) != A.Cols {
+		return nil, nil, errWrongLabelCount
+	}
+
+	groups := make(map[int][]int)
+	for col, label := range labels {
+		groups[label] = append(groups[label], col)
+	}
+	classes := make([]int, 0, len(groups))
+	for label := range groups {
+		classes = append(classes, label)
+	}
+	sort.Ints(classes)
+
+	return classes, groups, nil
+}
+
+// computes the mean of the given columns
+// returns a Rows x 1 column vector
+func meanOfColumns[T Float](A Dense[T], cols []int) Dense[T] {
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: 1,
+		Data: make([]T, A.Rows),
+	}
+	for i := range A.Rows {
+		row := A.Row(i)
+		var sum T
+		for _, col := range cols {
+			sum += row[col]
+		}
+		result.Data[i] = sum / T(len(cols))
+	}
+
+	return result
+}
+
+// adds weight * v * vᵀ to the symmetric matrix S
+func addOuterProduct[T Float](S Dense[T], v []T, weight T) {
+	for i, vi := range v {
+		Axpy(weight*vi, v, S.Row(i))
+	}
+}
+
+// computes the within-class scatter matrix Σ_c Σ_x∈c (x - μc)(x - μc)ᵀ
+// of the columns of A grouped by their labels
+// returns a Rows x Rows matrix or an error if there isn't one label for each column
+func WithinClassScatter[T Float](A Dense[T], labels []int) (Dense[T], error) {
+	classes, groups, err := groupColumns(A, labels)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: A.Rows,
+		Data: make([]T, A.Rows*A.Rows),
+	}
+	diff := make([]T, A.Rows)
+	for _, label := range classes {
+		mean := meanOfColumns(A, groups[label])
+		for _, col := range groups[label] {
+			for i := range A.Rows {
+				diff[i] = A.Data[i*A.Cols+col] - mean.Data[i]
+			}
+			addOuterProduct(result, diff, 1)
+		}
+	}
+
+	return result, nil
+}
+
+// computes the between-class scatter matrix Σ_c n_c (μc - μ)(μc - μ)ᵀ
+// of the columns of A grouped by their labels, where n_c is the size of class c
+// returns a Rows x Rows matrix or an error if there isn't one label for each column
+func BetweenClassScatter[T Float](A Dense[T], labels []int) (Dense[T], error) {
+	classes, groups, err := groupColumns(A, labels)
+	if err != nil {
+		return Dense[T]{}, err
+	}
+
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: A.Rows,
+		Data: make([]T, A.Rows*A.Rows),
+	}
+	if A.Cols == 0 {
+		return result, nil
+	}
+
+	total := RowMeans(A)
+	for _, label := range classes {
+		mean := meanOfColumns(A, groups[label])
+		if err := SubtractInPlace(mean, total); err != nil {
+			return Dense[T]{}, err
+		}
+		addOuterProduct(result, mean.Data, T(len(groups[label])))
+	}
+
+	return result, nil
+}
+
+// Welford accumulates the mean and variance of vectors one at a time with Welford's algorithm.
+// it needs only one pass and avoids the cancellation of summing squares, so it suits streams
+// of images that don't fit into memory. The sums are kept in float64 for both element types
+type Welford[T Float] struct {
+	count int
+	mean  []float64
+	m2    []float64 // sums of squared differences from the current mean
+}
+
+// creates an accumulator for vectors with the given number of elements
+func NewWelford[T Float](size int) *Welford[T] {
+	return &Welford[T]{
+		mean: make([]float64, size),
+		m2:   make([]float64, size),
+	}
+}
+
+// adds a vector to the statistics
+// returns an error if the vector has the wrong number of elements
+func (w *Welford[T]) Add(values []T) error {
+	if len(values) != len(w.mean) {
+		return errIncorrectSize
+	}
+
+	w.count++
+	n := float64(w.count)
+	for i, num := range values {
+		x := float64(num)
+		delta := x - w.mean[i]
+		w.mean[i] += delta / n
+		w.m2[i] += delta * (x - w.mean[i])
+	}
+
+	return nil
+}
+
+// returns the number of vectors added
+func (w *Welford[T]) Count() int {
+	return w.count
+}
+
+// returns the mean of the added vectors as a column vector
+func (w *Welford[T]) Mean() Dense[T] {
+	result := Dense[T]{
+		Rows: len(w.mean),
+		Cols: 1,
+		Data: make([]T, len(w.mean)),
+	}
+	for i, num := range w.mean {
+		result.Data[i] = T(num)
+	}
+
+	return result
+}
+
+// returns the variance of the added vectors as a column vector
+// returns an error if no more than ddof vectors have been added
+func (w *Welford[T]) Variance(ddof int) (Dense[T], error) {
+	if w.count-ddof <= 0 {
+		return Dense[T]{}, errNotEnoughSamples
+	}
+
+	result := Dense[T]{
+		Rows: len(w.m2),
+		Cols: 1,
+		Data: make([]T, len(w.m2)),
+	}
+	for i, num := range w.m2 {
+		result.Data[i] = T(num / float64(w.count-ddof))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func compareValues(t *testing.T, function string, got []float64, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d values, want %d", function, len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > EPSILON {
+			t.Errorf("%s: at index %d, got %f, want %f", function, i, got[i], want[i])
+		}
+	}
+}
+
+func TestMeansAndVariances(t *testing.T) {
+	A := Matrix{Rows: 2, Cols: 3, Data: []float64{
+		1, 2, 3,
+		4, 8, 12,
+	}}
+
+	compareValues(t, "RowMeans()", RowMeans(A).Data, []float64{2, 8})
+	compareValues(t, "ColMeans()", ColMeans(A).Data, []float64{2.5, 5, 7.5})
+
+	tests := []struct {
+		name       string
+		ddof       int
+		wantRowVar []float64
+		wantColVar []float64
+		wantRowStd []float64
+		wantRowErr error
+		wantColErr error
+	}{
+		{
+			name:       "population variance",
+			ddof:       0,
+			wantRowVar: []float64{2.0 / 3, 32.0 / 3},
+			wantColVar: []float64{2.25, 9, 20.25},
+			wantRowStd: []float64{math.Sqrt(2.0 / 3), math.Sqrt(32.0 / 3)},
+		},
+		{
+			name:       "sample variance",
+			ddof:       1,
+			wantRowVar: []float64{1, 16},
+			wantColVar: []float64{4.5, 18, 40.5},
+			wantRowStd: []float64{1, 4},
+		},
+		{
+			name:       "columns have too few samples",
+			ddof:       2,
+			wantRowVar: []float64{2, 32},
+			wantRowStd: []float64{math.Sqrt(2), math.Sqrt(32)},
+			wantRowErr: nil,
+			wantColErr: errNotEnoughSamples,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rowVar, err := RowVariances(A, tt.ddof)
+			if err != tt.wantRowErr {
+				t.Fatalf("RowVariances(): returned wrong error %v, want %v", err, tt.wantRowErr)
+			}
+			compareValues(t, "RowVariances()", rowVar.Data, tt.wantRowVar)
+			rowStd, _ := RowStdDevs(A, tt.ddof)
+			compareValues(t, "RowStdDevs()", rowStd.Data, tt.wantRowStd)
+
+			colVar, err := ColVariances(A, tt.ddof)
+			if err != tt.wantColErr {
+				t.Fatalf("ColVariances(): returned wrong error %v, want %v", err, tt.wantColErr)
+			}
+			if err == nil {
+				compareValues(t, "ColVariances()", colVar.Data, tt.wantColVar)
+				colStd, _ := ColStdDevs(A, tt.ddof)
+				for i := range colStd.Data {
+					if math.Abs(colStd.Data[i]*colStd.Data[i]-tt.wantColVar[i]) > EPSILON {
+						t.Errorf("ColStdDevs(): at index %d, got %f", i, colStd.Data[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStandardize(t *testing.T) {
+	A := Matrix{Rows: 2, Cols: 4, Data: []float64{
+		1, 2, 3, 4,
+		5, 5, 5, 5,
+	}}
+
+	Z, means, stds, err := Standardize(A)
+	if err != nil {
+		t.Fatalf("Standardize(): returned error %v", err)
+	}
+	std := math.Sqrt(1.25)
+	compareValues(t, "Standardize()", Z.Data, []float64{-1.5 / std, -0.5 / std, 0.5 / std, 1.5 / std, 0, 0, 0, 0})
+	compareValues(t, "Standardize() means", means.Data, []float64{2.5, 5})
+	compareValues(t, "Standardize() deviations", stds.Data, []float64{std, 0})
+
+	if _, _, _, err := Standardize(Matrix{Rows: 2, Cols: 0}); err != errNotEnoughSamples {
+		t.Errorf("Standardize(): returned wrong error %v, want %v", err, errNotEnoughSamples)
+	}
+}
+
+func TestScatterMatrices(t *testing.T) {
+	// two classes of two dimensional samples stored as columns
+	A := Matrix{Rows: 2, Cols: 4, Data: []float64{
+		1, 3, 6, 8,
+		1, 1, 4, 6,
+	}}
+	labels := []int{7, 7, 2, 2}
+
+	within, err := WithinClassScatter(A, labels)
+	if err != nil {
+		t.Fatalf("WithinClassScatter(): returned error %v", err)
+	}
+	// class 7 has mean (2, 1) and class 2 mean (7, 5)
+	compareValues(t, "WithinClassScatter()", within.Data, []float64{4, 2, 2, 2})
+
+	between, err := BetweenClassScatter(A, labels)
+	if err != nil {
+		t.Fatalf("BetweenClassScatter(): returned error %v", err)
+	}
+	// the total mean is (4.5, 3)
+	compareValues(t, "BetweenClassScatter()", between.Data, []float64{25, 20, 20, 16})
+
+	// the scatter matrices add up to the total scatter
+	centered, _ := DifferenceMatrix([]Matrix{
+		{Rows: 2, Cols: 1, Data: []float64{1, 1}},
+		{Rows: 2, Cols: 1, Data: []float64{3, 1}},
+		{Rows: 2, Cols: 1, Data: []float64{6, 4}},
+		{Rows: 2, Cols: 1, Data: []float64{8, 6}},
+	}, RowMeans(A))
+	total, _ := MulTransB(centered, centered)
+	sum, _ := Addition(within, between)
+	compareValues(t, "total scatter", sum.Data, total.Data)
+
+	if _, err := WithinClassScatter(A, []int{1, 2}); err != errWrongLabelCount {
+		t.Errorf("WithinClassScatter(): returned wrong error %v, want %v", err, errWrongLabelCount)
+	}
+	if _, err := BetweenClassScatter(A, []int{1, 2}); err != errWrongLabelCount {
+		t.Errorf("BetweenClassScatter(): returned wrong error %v, want %v", err, errWrongLabelCount)
+	}
+}
+
+func TestWelford(t *testing.T) {
+	samples := createRandomMatrix(500, 3, 1)
+	// a large offset makes the naive sum of squares lose precision
+	for i := range samples.Data {
+		samples.Data[i] += 1e9
+	}
+
+	w := NewWelford[float64](3)
+	for i := range samples.Rows {
+		if err := w.Add(samples.Row(i)); err != nil {
+			t.Fatalf("Add(): returned error %v", err)
+		}
+	}
+	if w.Count() != 500 {
+		t.Errorf("Count(): got %d, want 500", w.Count())
+	}
+
+	compareValues(t, "Mean()", w.Mean().Data, ColMeans(samples).Data)
+
+	variance, err := w.Variance(1)
+	if err != nil {
+		t.Fatalf("Variance(): returned error %v", err)
+	}
+	want, _ := ColVariances(samples, 1)
+	for i := range want.Data {
+		if math.Abs(variance.Data[i]-want.Data[i]) > 1e-6*want.Data[i] {
+			t.Errorf("Variance(): at index %d, got %f, want %f", i, variance.Data[i], want.Data[i])
+		}
+	}
+
+	if err := w.Add([]float64{1, 2}); err != errIncorrectSize {
+		t.Errorf("Add(): returned wrong error %v, want %v", err, errIncorrectSize)
+	}
+	if _, err := NewWelford[float32](2).Variance(1); err != errNotEnoughSamples {
+		t.Errorf("Variance(): returned wrong error %v, want %v", err, errNotEnoughSamples)
+	}
+}
@@ -0,0 +1,113 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+)
+
+// the functions in this file multiply with a transposed operand without building the
+// transpose. Every element is summed in the same order as in Multiplication
+
+// computes Aᵀ * B without transposing A
+// returns a new matrix containing the result or an error if dimensions are incompatible
+func MulTransA[T Float](A, B Dense[T]) (Dense[T], error) {
+	if A.Rows != B.Rows {
+		return Dense[T]{}, errIncorrectSize
+	}
+
+	result := Dense[T]{
+		Rows: A.Cols,
+		Cols: B.Cols,
+		Data: make([]T, A.Cols*B.Cols),
+	}
+
+	// row k of A and row k of B hold the k:th term of every element of the result,
+	// so walking k in the outer loop keeps all reads contiguous
+	for k := range A.Rows {
+		aRow := A.Data[k*A.Cols : (k+1)*A.Cols]
+		bRow := B.Data[k*B.Cols : (k+1)*B.Cols]
+		for i, a := range aRow {
+			resultRow := result.Data[i*B.Cols : (i+1)*B.Cols]
+			for j, b := range bRow {
+				resultRow[j] += a * b
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// computes A * Bᵀ without transposing B
+// returns a new matrix containing the result or an error if dimensions are incompatible
+func MulTransB[T Float](A, B Dense[T]) (Dense[T], error) {
+	if A.Cols != B.Cols {
+		return Dense[T]{}, errIncorrectSize
+	}
+
+	result := Dense[T]{
+		Rows: A.Rows,
+		Cols: B.Rows,
+		Data: make([]T, A.Rows*B.Rows),
+	}
+
+	// every element is a dot product of a row of A and a row of B
+	for i := range A.Rows {
+		aRow := A.Data[i*A.Cols : (i+1)*A.Cols]
+		for j := range B.Rows {
+			bRow := B.Data[j*B.Cols : (j+1)*B.Cols]
+			var sum T = 0
+			for k, a := range aRow {
+				sum += a * bRow[k]
+			}
+			result.Data[i*B.Rows+j] = sum
+		}
+	}
+
+	return result, nil
+}
+
+// computes the symmetric Gram matrix Aᵀ * A. Only the upper triangle is computed
+// and it is mirrored to the lower triangle. Large matrices split the rows between goroutines
+// returns a new matrix containing the result
+func Gram[T Float](A Dense[T]) Dense[T] {
+	n := A.Cols
+	result := Dense[T]{
+		Rows: n,
+		Cols: n,
+		Data: make([]T, n*n),
+	}
+
+	workers := 1
+	if A.Rows*n*n/2 >= parallelThreshold {
+		workers = max(1, min(runtime.GOMAXPROCS(0), n))
+	}
+
+	// the rows of the triangle get shorter, so every worker takes every workers:th row
+	// to get about the same amount of work
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			for k := range A.Rows {
+				aRow := A.Data[k*n : (k+1)*n]
+				for i := first; i < n; i += workers {
+					a := aRow[i]
+					resultRow := result.Data[i*n : (i+1)*n]
+					for j := i; j < n; j++ {
+						resultRow[j] += a * aRow[j]
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			result.Data[j*n+i] = result.Data[i*n+j]
+		}
+	}
+
+	return result
+}
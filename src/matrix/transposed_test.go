@@ -0,0 +1,107 @@
+package matrix
+
+import (
+	"testing"
+)
+
+func TestMulTransAAndB(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       Matrix
+		B       Matrix
+		wantErr error
+	}{
+		{
+			name:    "output matches multiplication with the transpose",
+			A:       createRandomMatrix(7, 5, 1),
+			B:       createRandomMatrix(7, 3, 2),
+			wantErr: nil,
+		},
+		{
+			name:    "output matches with a tall matrix and a vector",
+			A:       createRandomMatrix(10304, 20, 3),
+			B:       createRandomMatrix(10304, 1, 4),
+			wantErr: nil,
+		},
+		{
+			name:    "incompatible sizes fail",
+			A:       createRandomMatrix(7, 5, 1),
+			B:       createRandomMatrix(6, 3, 2),
+			wantErr: errIncorrectSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MulTransA(tt.A, tt.B)
+			if err != tt.wantErr {
+				t.Fatalf("MulTransA(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				want, _ := Multiplication(Transpose(tt.A), tt.B)
+				compareExactly(t, "MulTransA()", result, want)
+			}
+
+			// AᵀB is the transpose of BᵀA, which MulTransB computes from the transposed operands
+			AT := Transpose(tt.A)
+			BT := Transpose(tt.B)
+			result, err = MulTransB(AT, BT)
+			if err != tt.wantErr {
+				t.Fatalf("MulTransB(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				want, _ := Multiplication(AT, tt.B)
+				compareExactly(t, "MulTransB()", result, want)
+			}
+		})
+	}
+}
+
+func TestGram(t *testing.T) {
+	tests := []struct {
+		name string
+		A    Matrix
+	}{
+		{
+			name: "small matrix",
+			A:    Matrix{Rows: 3, Cols: 2, Data: []float64{1, 2, 3, 4, 5, 6}},
+		},
+		{
+			name: "matrix with the shape of training data",
+			A:    createRandomMatrix(10304, 40, 5),
+		},
+		{
+			name: "single row",
+			A:    createRandomMatrix(1, 9, 6),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Gram(tt.A)
+			want, _ := Multiplication(Transpose(tt.A), tt.A)
+			compareExactly(t, "Gram()", result, want)
+
+			for i := range result.Rows {
+				for j := range result.Cols {
+					if result.Data[i*result.Cols+j] != result.Data[j*result.Cols+i] {
+						t.Fatalf("Gram(): result is not symmetric at (%d, %d)", i, j)
+					}
+				}
+			}
+		})
+	}
+}
+
+// the fused kernels sum in the same order as Multiplication so the results must be identical
+func compareExactly(t *testing.T, function string, result, want Matrix) {
+	t.Helper()
+	if result.Rows != want.Rows || result.Cols != want.Cols {
+		t.Fatalf("%s: got %dx%d matrix, want %dx%d", function, result.Rows, result.Cols, want.Rows, want.Cols)
+	}
+	for i := range want.Data {
+		if result.Data[i] != want.Data[i] {
+			t.Fatalf("%s: at index %d, got %f, want %f", function, i, result.Data[i], want.Data[i])
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package matrix
+
+import (
+	"math"
+)
+
+// the functions in this file treat slices and the data of matrices as vectors.
+// the loops are unrolled by four with separate sums so long pixel vectors are
+// processed without waiting on a single accumulator
+
+// computes the dot product of x and y. y must be at least as long as x
+func Dot[T Float](x, y []T) T {
+	y = y[:len(x)]
+	var s0, s1, s2, s3 T
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		s0 += x[i] * y[i]
+		s1 += x[i+1] * y[i+1]
+		s2 += x[i+2] * y[i+2]
+		s3 += x[i+3] * y[i+3]
+	}
+	for ; i < len(x); i++ {
+		s0 += x[i] * y[i]
+	}
+
+	return (s0 + s1) + (s2 + s3)
+}
+
+// computes the sum of the absolute values of x
+func Norm1[T Float](x []T) T {
+	var s0, s1, s2, s3 T
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		s0 += Abs(x[i])
+		s1 += Abs(x[i+1])
+		s2 += Abs(x[i+2])
+		s3 += Abs(x[i+3])
+	}
+	for ; i < len(x); i++ {
+		s0 += Abs(x[i])
+	}
+
+	return (s0 + s1) + (s2 + s3)
+}
+
+// computes the euclidean length of x
+func Norm2[T Float](x []T) T {
+	return T(math.Sqrt(float64(Dot(x, x))))
+}
+
+// computes the largest absolute value of x
+func NormInf[T Float](x []T) T {
+	var result T
+	for _, num := range x {
+		result = max(result, Abs(num))
+	}
+
+	return result
+}
+
+// scales x to unit length in place
+// returns the length before scaling. A zero vector is left unchanged
+func Normalize[T Float](x []T) T {
+	norm := Norm2(x)
+	if norm == 0 {
+		return 0
+	}
+
+	scale := 1 / norm
+	for i := range x {
+		x[i] *= scale
+	}
+
+	return norm
+}
+
+// computes the cosine of the angle between x and y
+// returns 0 if either vector is zero
+func Cosine[T Float](x, y []T) T {
+	norms := Norm2(x) * Norm2(y)
+	if norms == 0 {
+		return 0
+	}
+
+	return Dot(x, y) / norms
+}
+
+// computes the squared euclidean distance between x and y. y must be at least as long as x
+func SquaredEuclidean[T Float](x, y []T) T {
+	y = y[:len(x)]
+	var s0, s1, s2, s3 T
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		d0 := x[i] - y[i]
+		d1 := x[i+1] - y[i+1]
+		d2 := x[i+2] - y[i+2]
+		d3 := x[i+3] - y[i+3]
+		s0 += d0 * d0
+		s1 += d1 * d1
+		s2 += d2 * d2
+		s3 += d3 * d3
+	}
+	for ; i < len(x); i++ {
+		d := x[i] - y[i]
+		s0 += d * d
+	}
+
+	return (s0 + s1) + (s2 + s3)
+}
+
+// computes y = alpha * x + y in place. y must be at least as long as x
+func Axpy[T Float](alpha T, x, y []T) {
+	y = y[:len(x)]
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		y[i] += alpha * x[i]
+		y[i+1] += alpha * x[i+1]
+		y[i+2] += alpha * x[i+2]
+		y[i+3] += alpha * x[i+3]
+	}
+	for ; i < len(x); i++ {
+		y[i] += alpha * x[i]
+	}
+}
+
+// returns the absolute value of x
+func Abs[T Float](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// checks that A and B are column vectors of the same length
+func sameVectors[T Float](A, B Dense[T]) bool {
+	return A.Cols == 1 && B.Cols == 1 && A.Rows == B.Rows
+}
+
+// computes the dot product of two column vectors
+// returns the result or an error if the vectors have different sizes
+func (A Dense[T]) Dot(B Dense[T]) (T, error) {
+	if !sameVectors(A, B) {
+		return 0, errIncorrectSize
+	}
+
+	return Dot(A.Data, B.Data), nil
+}
+
+// computes the sum of the absolute values of the elements
+func (A Dense[T]) Norm1() T {
+	return Norm1(A.Data)
+}
+
+// computes the euclidean length of a vector or the Frobenius norm of a matrix
+func (A Dense[T]) Norm2() T {
+	return Norm2(A.Data)
+}
+
+// computes the largest absolute value of the elements
+func (A Dense[T]) NormInf() T {
+	return NormInf(A.Data)
+}
+
+// scales the vector to unit length in place
+// returns the length before scaling. A zero vector is left unchanged
+func (A Dense[T]) Normalize() T {
+	return Normalize(A.Data)
+}
+
+// computes the cosine of the angle between two column vectors
+// returns the result or an error if the vectors have different sizes
+func (A Dense[T]) Cosine(B Dense[T]) (T, error) {
+	if !sameVectors(A, B) {
+		return 0, errIncorrectSize
+	}
+
+	return Cosine(A.Data, B.Data), nil
+}
+
+// computes the squared euclidean distance between two column vectors
+// returns the result or an error if the vectors have different sizes
+func (A Dense[T]) SquaredEuclidean(B Dense[T]) (T, error) {
+	if !sameVectors(A, B) {
+		return 0, errIncorrectSize
+	}
+
+	return SquaredEuclidean(A.Data, B.Data), nil
+}
+
+// adds alpha * X to the column vector in place
+// returns an error if the vectors have different sizes
+func (A Dense[T]) Axpy(alpha T, X Dense[T]) error {
+	if !sameVectors(A, X) {
+		return errIncorrectSize
+	}
+
+	Axpy(alpha, X.Data, A.Data)
+	return nil
+}
@@ -0,0 +1,176 @@
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVectorFunctions(t *testing.T) {
+	tests := []struct {
+		name          string
+		x             []float64
+		y             []float64
+		wantDot       float64
+		wantNorm1     float64
+		wantNorm2     float64
+		wantNormInf   float64
+		wantCosine    float64
+		wantEuclidean float64
+	}{
+		{
+			name:          "short vectors use only the remainder loop",
+			x:             []float64{3, -4},
+			y:             []float64{4, 3},
+			wantDot:       0,
+			wantNorm1:     7,
+			wantNorm2:     5,
+			wantNormInf:   4,
+			wantCosine:    0,
+			wantEuclidean: 50,
+		},
+		{
+			name:          "unrolled loop and remainder",
+			x:             []float64{1, 2, 3, 4, 5, 6, 7},
+			y:             []float64{1, 1, 1, 1, 1, 1, -1},
+			wantDot:       14,
+			wantNorm1:     28,
+			wantNorm2:     math.Sqrt(140),
+			wantNormInf:   7,
+			wantCosine:    14 / (math.Sqrt(140) * math.Sqrt(7)),
+			wantEuclidean: 0 + 1 + 4 + 9 + 16 + 25 + 64,
+		},
+		{
+			name:          "parallel vectors",
+			x:             []float64{-1, -2, -3, -4},
+			y:             []float64{2, 4, 6, 8},
+			wantDot:       -60,
+			wantNorm1:     10,
+			wantNorm2:     math.Sqrt(30),
+			wantNormInf:   4,
+			wantCosine:    -1,
+			wantEuclidean: 270,
+		},
+		{
+			name:          "zero vector has zero cosine",
+			x:             []float64{0, 0, 0},
+			y:             []float64{1, 2, 3},
+			wantDot:       0,
+			wantNorm1:     0,
+			wantNorm2:     0,
+			wantNormInf:   0,
+			wantCosine:    0,
+			wantEuclidean: 14,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := []struct {
+				function string
+				got      float64
+				want     float64
+			}{
+				{"Dot()", Dot(tt.x, tt.y), tt.wantDot},
+				{"Norm1()", Norm1(tt.x), tt.wantNorm1},
+				{"Norm2()", Norm2(tt.x), tt.wantNorm2},
+				{"NormInf()", NormInf(tt.x), tt.wantNormInf},
+				{"Cosine()", Cosine(tt.x, tt.y), tt.wantCosine},
+				{"SquaredEuclidean()", SquaredEuclidean(tt.x, tt.y), tt.wantEuclidean},
+			}
+			for _, check := range checks {
+				if math.Abs(check.got-check.want) > EPSILON {
+					t.Errorf("%s: got %f, want %f", check.function, check.got, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeAndAxpy(t *testing.T) {
+	x := []float64{3, 0, 4, 0, 0}
+	norm := Normalize(x)
+	if math.Abs(norm-5) > EPSILON {
+		t.Errorf("Normalize(): returned norm %f, want 5", norm)
+	}
+	if math.Abs(Norm2(x)-1) > EPSILON {
+		t.Errorf("Normalize(): result has length %f, want 1", Norm2(x))
+	}
+
+	zero := []float64{0, 0}
+	if Normalize(zero) != 0 || zero[0] != 0 || zero[1] != 0 {
+		t.Errorf("Normalize(): zero vector changed to %v", zero)
+	}
+
+	y := []float64{1, 1, 1, 1, 1}
+	Axpy(2, []float64{1, 2, 3, 4, 5}, y)
+	want := []float64{3, 5, 7, 9, 11}
+	for i := range want {
+		if y[i] != want[i] {
+			t.Errorf("Axpy(): at index %d, got %f, want %f", i, y[i], want[i])
+		}
+	}
+}
+
+func TestVectorMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       Matrix
+		B       Matrix
+		wantErr error
+	}{
+		{
+			name:    "column vectors of the same length",
+			A:       Matrix{Rows: 3, Cols: 1, Data: []float64{1, 2, 2}},
+			B:       Matrix{Rows: 3, Cols: 1, Data: []float64{2, 0, 1}},
+			wantErr: nil,
+		},
+		{
+			name:    "vectors with different lengths fail",
+			A:       Matrix{Rows: 3, Cols: 1, Data: []float64{1, 2, 2}},
+			B:       Matrix{Rows: 2, Cols: 1, Data: []float64{2, 0}},
+			wantErr: errIncorrectSize,
+		},
+		{
+			name:    "row vectors fail",
+			A:       Matrix{Rows: 1, Cols: 3, Data: []float64{1, 2, 2}},
+			B:       Matrix{Rows: 1, Cols: 3, Data: []float64{2, 0, 1}},
+			wantErr: errIncorrectSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dot, err := tt.A.Dot(tt.B)
+			if err != tt.wantErr {
+				t.Errorf("Dot(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			_, err = tt.A.Cosine(tt.B)
+			if err != tt.wantErr {
+				t.Errorf("Cosine(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			distance, err := tt.A.SquaredEuclidean(tt.B)
+			if err != tt.wantErr {
+				t.Errorf("SquaredEuclidean(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err := tt.A.Axpy(1, tt.B); err != tt.wantErr {
+				t.Errorf("Axpy(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if dot != 4 {
+				t.Errorf("Dot(): got %f, want 4", dot)
+			}
+			if distance != 6 {
+				t.Errorf("SquaredEuclidean(): got %f, want 6", distance)
+			}
+			if tt.A.Data[0] != 3 || tt.A.Data[1] != 2 || tt.A.Data[2] != 3 {
+				t.Errorf("Axpy(): got %v, want [3 2 3]", tt.A.Data)
+			}
+			if math.Abs(tt.A.Norm2()-math.Sqrt(22)) > EPSILON {
+				t.Errorf("Norm2(): got %f, want %f", tt.A.Norm2(), math.Sqrt(22))
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package matrix
+
+// DenseView is a window into the data of a matrix. Element (i, j) of the view is stored at
+// Data[i*Stride+j], so columns and submatrices can be used without copying. Writing to
+// a view writes to the matrix it was taken from
+type DenseView[T Float] struct {
+	Rows   int
+	Cols   int
+	Stride int
+	Data   []T
+}
+
+// view of a double precision matrix
+type View = DenseView[float64]
+
+// returns a view of the whole matrix
+func (A Dense[T]) View() DenseView[T] {
+	return DenseView[T]{
+		Rows:   A.Rows,
+		Cols:   A.Cols,
+		Stride: A.Cols,
+		Data:   A.Data,
+	}
+}
+
+// returns row i of the matrix as a slice sharing the data of the matrix
+func (A Dense[T]) Row(i int) []T {
+	return A.Data[i*A.Cols : (i+1)*A.Cols]
+}
+
+// returns column j of the matrix as a Rows x 1 view
+func (A Dense[T]) Col(j int) DenseView[T] {
+	return A.View().Col(j)
+}
+
+// copies the values of a column vector into column j of the matrix
+// returns an error if the vector doesn't have one value for each row
+func (A Dense[T]) SetCol(j int, values DenseView[T]) error {
+	if values.Rows != A.Rows || values.Cols != 1 {
+		return errIncorrectSize
+	}
+
+	for i := range A.Rows {
+		A.Data[i*A.Cols+j] = values.Data[i*values.Stride]
+	}
+
+	return nil
+}
+
+// returns a view of the rows x cols submatrix with the top left corner at (row, col)
+// returns an error if the submatrix doesn't fit inside the matrix
+func (A Dense[T]) Slice(row, col, rows, cols int) (DenseView[T], error) {
+	return A.View().Slice(row, col, rows, cols)
+}
+
+// returns the element at row i and column j
+func (v DenseView[T]) At(i, j int) T {
+	return v.Data[i*v.Stride+j]
+}
+
+// sets the element at row i and column j
+func (v DenseView[T]) Set(i, j int, value T) {
+	v.Data[i*v.Stride+j] = value
+}
+
+// returns row i of the view as a slice sharing the data of the view
+func (v DenseView[T]) Row(i int) []T {
+	return v.Data[i*v.Stride : i*v.Stride+v.Cols]
+}
+
+// returns column j of the view as a Rows x 1 view
+func (v DenseView[T]) Col(j int) DenseView[T] {
+	if v.Rows == 0 {
+		return DenseView[T]{Cols: 1, Stride: v.Stride}
+	}
+
+	return DenseView[T]{
+		Rows:   v.Rows,
+		Cols:   1,
+		Stride: v.Stride,
+		Data:   v.Data[j : (v.Rows-1)*v.Stride+j+1],
+	}
+}
+
+// returns a view of the rows x cols submatrix with the top left corner at (row, col)
+// returns an error if the submatrix doesn't fit inside the view
+func (v DenseView[T]) Slice(row, col, rows, cols int) (DenseView[T], error) {
+	if row < 0 || col < 0 || rows < 0 || cols < 0 || row+rows > v.Rows || col+cols > v.Cols {
+		return DenseView[T]{}, errIncorrectSize
+	}
+	if rows == 0 || cols == 0 {
+		return DenseView[T]{Rows: rows, Cols: cols, Stride: v.Stride}, nil
+	}
+
+	start := row*v.Stride + col
+	return DenseView[T]{
+		Rows:   rows,
+		Cols:   cols,
+		Stride: v.Stride,
+		Data:   v.Data[start : start+(rows-1)*v.Stride+cols],
+	}, nil
+}
+
+// copies the elements of the view into a new matrix
+func (v DenseView[T]) Copy() Dense[T] {
+	result := Dense[T]{
+		Rows: v.Rows,
+		Cols: v.Cols,
+		Data: make([]T, v.Rows*v.Cols),
+	}
+
+	for i := range v.Rows {
+		copy(result.Row(i), v.Row(i))
+	}
+
+	return result
+}
+
+// copies the elements of src into dst
+// returns an error if the views have different sizes
+func CopyView[T Float](dst, src DenseView[T]) error {
+	if dst.Rows != src.Rows || dst.Cols != src.Cols {
+		return errIncorrectSize
+	}
+
+	for i := range src.Rows {
+		copy(dst.Row(i), src.Row(i))
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package matrix
+
+import (
+	"testing"
+)
+
+func TestRowAndCol(t *testing.T) {
+	A := Matrix{Rows: 3, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}}
+
+	row := A.Row(1)
+	wantRow := []float64{4, 5, 6}
+	for i := range wantRow {
+		if row[i] != wantRow[i] {
+			t.Errorf("Row(): at index %d, got %f, want %f", i, row[i], wantRow[i])
+		}
+	}
+
+	col := A.Col(2)
+	if col.Rows != 3 || col.Cols != 1 {
+		t.Fatalf("Col(): got %dx%d view, want 3x1", col.Rows, col.Cols)
+	}
+	wantCol := []float64{3, 6, 9}
+	for i := range wantCol {
+		if col.At(i, 0) != wantCol[i] {
+			t.Errorf("Col(): at row %d, got %f, want %f", i, col.At(i, 0), wantCol[i])
+		}
+	}
+
+	// views share the data of the matrix
+	col.Set(0, 0, 30)
+	row[0] = 40
+	if A.Data[2] != 30 || A.Data[3] != 40 {
+		t.Errorf("Col() and Row(): writes didn't reach the matrix, got %v", A.Data)
+	}
+}
+
+func TestSetCol(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       Matrix
+		j       int
+		values  View
+		want    []float64
+		wantErr error
+	}{
+		{
+			name:    "column is replaced",
+			A:       Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			j:       1,
+			values:  Matrix{Rows: 2, Cols: 1, Data: []float64{8, 9}}.View(),
+			want:    []float64{1, 8, 3, 9},
+			wantErr: nil,
+		},
+		{
+			name:    "column of another matrix is copied",
+			A:       Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			j:       0,
+			values:  Matrix{Rows: 2, Cols: 3, Data: []float64{0, 0, 5, 0, 0, 6}}.Col(2),
+			want:    []float64{5, 2, 6, 4},
+			wantErr: nil,
+		},
+		{
+			name:    "vector with wrong size fails",
+			A:       Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			j:       0,
+			values:  Matrix{Rows: 1, Cols: 2, Data: []float64{8, 9}}.View(),
+			want:    []float64{1, 2, 3, 4},
+			wantErr: errIncorrectSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.A.SetCol(tt.j, tt.values)
+			if err != tt.wantErr {
+				t.Errorf("SetCol(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			for i := range tt.want {
+				if tt.A.Data[i] != tt.want[i] {
+					t.Errorf("SetCol(): at index %d, got %f, want %f", i, tt.A.Data[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	A := Matrix{Rows: 3, Cols: 4, Data: []float64{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	}}
+
+	tests := []struct {
+		name                 string
+		row, col, rows, cols int
+		want                 Matrix
+		wantErr              error
+	}{
+		{
+			name: "inner submatrix",
+			row:  1, col: 1, rows: 2, cols: 2,
+			want:    Matrix{Rows: 2, Cols: 2, Data: []float64{6, 7, 10, 11}},
+			wantErr: nil,
+		},
+		{
+			name: "last column",
+			row:  0, col: 3, rows: 3, cols: 1,
+			want:    Matrix{Rows: 3, Cols: 1, Data: []float64{4, 8, 12}},
+			wantErr: nil,
+		},
+		{
+			name: "whole matrix",
+			row:  0, col: 0, rows: 3, cols: 4,
+			want:    A,
+			wantErr: nil,
+		},
+		{
+			name: "submatrix outside the matrix fails",
+			row:  2, col: 2, rows: 2, cols: 2,
+			want:    Matrix{},
+			wantErr: errIncorrectSize,
+		},
+		{
+			name: "negative position fails",
+			row:  -1, col: 0, rows: 1, cols: 1,
+			want:    Matrix{},
+			wantErr: errIncorrectSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := A.Slice(tt.row, tt.col, tt.rows, tt.cols)
+			if err != tt.wantErr {
+				t.Fatalf("Slice(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			result := view.Copy()
+			if result.Rows != tt.want.Rows || result.Cols != tt.want.Cols {
+				t.Fatalf("Slice(): got %dx%d matrix, want %dx%d", result.Rows, result.Cols, tt.want.Rows, tt.want.Cols)
+			}
+			for i := range tt.want.Data {
+				if result.Data[i] != tt.want.Data[i] {
+					t.Errorf("Slice(): at index %d, got %f, want %f", i, result.Data[i], tt.want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCopyView(t *testing.T) {
+	dst := Matrix{Rows: 3, Cols: 3, Data: make([]float64, 9)}
+	src := Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}}
+
+	corner, _ := dst.Slice(1, 1, 2, 2)
+	if err := CopyView(corner, src.View()); err != nil {
+		t.Fatalf("CopyView(): returned error %v", err)
+	}
+	want := []float64{0, 0, 0, 0, 1, 2, 0, 3, 4}
+	for i := range want {
+		if dst.Data[i] != want[i] {
+			t.Errorf("CopyView(): at index %d, got %f, want %f", i, dst.Data[i], want[i])
+		}
+	}
+
+	if err := CopyView(dst.View(), src.View()); err != errIncorrectSize {
+		t.Errorf("CopyView(): returned wrong error %v, want %v", err, errIncorrectSize)
+	}
+}
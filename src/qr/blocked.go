@@ -0,0 +1,210 @@
+package qr
+
+import (
+	"math"
+
+	m "face_recognition/matrix"
+)
+
+// number of columns whose reflectors are accumulated into one block
+const qrBlockSize = 32
+
+// reflectors of one block in compact WY form. The product H₁H₂...Hₖ of the reflectors equals I - V * T * Vᵀ
+type wyBlock[T m.Float] struct {
+	start int        // first row and column of the block
+	V     m.Dense[T] // reflectors as columns with a unit first element and zeros above it
+	T     m.Dense[T] // upper triangular block factor
+}
+
+// performs QR decomposition with blocked Householder reflections. The columns are factored
+// qrBlockSize at a time and the reflectors of a block are accumulated into the compact WY form
+// I - V * T * Vᵀ, so the rest of the matrix is updated with three matrix multiplications per
+// block instead of one pass per reflector. This makes much better use of the cache for tall
+// matrices like the difference matrix. With thin set Q is m x min(m, n) and R is min(m, n) x n,
+// otherwise Q is m x m and R is m x n
+// returns orthogonal matrix Q and upper triangular matrix R such that A = QR
+func BlockedQR[T m.Float](A m.Dense[T], thin bool) (m.Dense[T], m.Dense[T], error) {
+	rows, cols := A.Rows, A.Cols
+	size := min(rows, cols)
+
+	W := m.Dense[T]{
+		Rows: rows,
+		Cols: cols,
+		Data: make([]T, len(A.Data)),
+	}
+	copy(W.Data, A.Data)
+
+	var blocks []wyBlock[T]
+	for start := 0; start < size; start += qrBlockSize {
+		width := min(qrBlockSize, size-start)
+		tau := factorPanel(W, start, width)
+		block := newWYBlock(W, start, width, tau)
+		blocks = append(blocks, block)
+
+		if start+width == cols {
+			continue
+		}
+		// Qᵀ of the block is I - V * Tᵀ * Vᵀ
+		trailing, err := W.Slice(start, start+width, rows-start, cols-start-width)
+		if err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+		if err := applyWYBlock(trailing, block.V, block.T, true); err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+	}
+
+	qCols, rRows := rows, rows
+	if thin {
+		qCols, rRows = size, size
+	}
+
+	R := m.Dense[T]{
+		Rows: rRows,
+		Cols: cols,
+		Data: make([]T, rRows*cols),
+	}
+	for i := range min(rRows, size) {
+		copy(R.Row(i)[i:], W.Row(i)[i:])
+	}
+
+	// Q is built by applying the blocks to the first columns of the identity in reverse order.
+	// the columns before a block are still unit vectors that the block doesn't change
+	Q := m.Dense[T]{
+		Rows: rows,
+		Cols: qCols,
+		Data: make([]T, rows*qCols),
+	}
+	for i := range min(rows, qCols) {
+		Q.Data[i*qCols+i] = 1
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		block := blocks[i]
+		view, err := Q.Slice(block.start, block.start, rows-block.start, qCols-block.start)
+		if err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+		if err := applyWYBlock(view, block.V, block.T, false); err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+	}
+
+	return Q, R, nil
+}
+
+// factors width columns of W starting from the diagonal element at start with one reflector per column.
+// each reflector is only applied to the rest of the panel. R is left on and above the diagonal and the
+// reflectors below it without their unit first element
+// returns the scaling factors tau of the reflectors Hᵢ = I - tau * v * vᵀ
+func factorPanel[T m.Float](W m.Dense[T], start, width int) []T {
+	tau := make([]T, width)
+	products := make([]T, width)
+	for j := range width {
+		col := start + j
+
+		alpha := W.Data[col*W.Cols+col]
+		xnorm := 0.0
+		for i := col + 1; i < W.Rows; i++ {
+			value := float64(W.Data[i*W.Cols+col])
+			xnorm += value * value
+		}
+		if xnorm == 0 {
+			// the column is already reduced
+			continue
+		}
+
+		beta := T(math.Hypot(float64(alpha), math.Sqrt(xnorm)))
+		if alpha >= 0 {
+			beta = -beta
+		}
+		tau[j] = (beta - alpha) / beta
+		scale := 1 / (alpha - beta)
+		for i := col + 1; i < W.Rows; i++ {
+			W.Data[i*W.Cols+col] *= scale
+		}
+		W.Data[col*W.Cols+col] = beta
+
+		// the remaining panel columns are updated row by row to read contiguous memory
+		rest := products[:width-j-1]
+		copy(rest, W.Row(col)[col+1:start+width])
+		for i := col + 1; i < W.Rows; i++ {
+			m.Axpy(W.Data[i*W.Cols+col], W.Row(i)[col+1:start+width], rest)
+		}
+		for i := range rest {
+			rest[i] *= tau[j]
+		}
+		m.Axpy(-1, rest, W.Row(col)[col+1:start+width])
+		for i := col + 1; i < W.Rows; i++ {
+			m.Axpy(-W.Data[i*W.Cols+col], rest, W.Row(i)[col+1:start+width])
+		}
+	}
+	return tau
+}
+
+// builds the compact WY form of the reflectors stored in W by factorPanel
+// the columns of T are formed one reflector at a time with T[:j, j] = -tau[j] * T[:j, :j] * V[:, :j]ᵀ * v[j]
+func newWYBlock[T m.Float](W m.Dense[T], start, width int, tau []T) wyBlock[T] {
+	rows := W.Rows - start
+	V := m.Dense[T]{
+		Rows: rows,
+		Cols: width,
+		Data: make([]T, rows*width),
+	}
+	// row i of the first rows only has the reflectors before it and the unit element
+	for i := range rows {
+		row := V.Row(i)
+		if i < width {
+			row[i] = 1
+			copy(row[:i], W.Row(start + i)[start:start+i])
+		} else {
+			copy(row, W.Row(start + i)[start:start+width])
+		}
+	}
+
+	// the sizes always match so the error can be ignored
+	G, _ := m.MulTransA(V, V)
+	factor := m.Dense[T]{
+		Rows: width,
+		Cols: width,
+		Data: make([]T, width*width),
+	}
+	for j := range width {
+		factor.Data[j*width+j] = tau[j]
+		for i := range j {
+			var sum T
+			for l := i; l < j; l++ {
+				sum += factor.Data[i*width+l] * G.Data[l*width+j]
+			}
+			factor.Data[i*width+j] = -tau[j] * sum
+		}
+	}
+
+	return wyBlock[T]{start: start, V: V, T: factor}
+}
+
+// applies the block reflector I - V * T * Vᵀ, or its transpose with transpose set, to the
+// rows of C in place using matrix multiplications
+// returns an error if the sizes don't match
+func applyWYBlock[T m.Float](C m.DenseView[T], V, factor m.Dense[T], transpose bool) error {
+	dense := C.Copy()
+	product, err := m.MulTransA(V, dense)
+	if err != nil {
+		return err
+	}
+	if transpose {
+		product, err = m.MulTransA(factor, product)
+	} else {
+		product, err = m.Multiplication(factor, product)
+	}
+	if err != nil {
+		return err
+	}
+	update, err := m.Multiplication(V, product)
+	if err != nil {
+		return err
+	}
+	if err := m.SubtractInPlace(dense, update); err != nil {
+		return err
+	}
+	return m.CopyView(C, dense.View())
+}
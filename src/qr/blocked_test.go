@@ -0,0 +1,109 @@
+package qr
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestBlockedQR(t *testing.T) {
+	zeroColumn := createRandomMatrix(6, 4, 3)
+	for i := range zeroColumn.Rows {
+		zeroColumn.Data[i*zeroColumn.Cols+1] = 0
+	}
+
+	tests := []struct {
+		name  string
+		A     m.Matrix
+		thin  bool
+		wantQ [2]int
+		wantR [2]int
+	}{
+		{
+			name:  "square matrix",
+			A:     m.Matrix{Rows: 3, Cols: 3, Data: []float64{12, -51, 4, 6, 167, -68, -4, 24, -41}},
+			wantQ: [2]int{3, 3},
+			wantR: [2]int{3, 3},
+		},
+		{
+			name:  "tall matrix with full Q",
+			A:     createRandomMatrix(50, 10, 1),
+			wantQ: [2]int{50, 50},
+			wantR: [2]int{50, 10},
+		},
+		{
+			name:  "tall matrix with thin Q",
+			A:     createRandomMatrix(50, 10, 1),
+			thin:  true,
+			wantQ: [2]int{50, 10},
+			wantR: [2]int{10, 10},
+		},
+		{
+			name:  "wide matrix",
+			A:     createRandomMatrix(5, 9, 2),
+			thin:  true,
+			wantQ: [2]int{5, 5},
+			wantR: [2]int{5, 9},
+		},
+		{
+			name:  "zero column",
+			A:     zeroColumn,
+			wantQ: [2]int{6, 6},
+			wantR: [2]int{6, 4},
+		},
+		{
+			name:  "several blocks",
+			A:     createRandomMatrix(120, 75, 4),
+			thin:  true,
+			wantQ: [2]int{120, 75},
+			wantR: [2]int{75, 75},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, err := BlockedQR(tt.A, tt.thin)
+			if err != nil {
+				t.Fatalf("BlockedQR(): returned error %v", err)
+			}
+			if Q.Rows != tt.wantQ[0] || Q.Cols != tt.wantQ[1] || R.Rows != tt.wantR[0] || R.Cols != tt.wantR[1] {
+				t.Fatalf("BlockedQR(): returned Q %dx%d and R %dx%d, want %v and %v", Q.Rows, Q.Cols, R.Rows, R.Cols, tt.wantQ, tt.wantR)
+			}
+
+			checkOrthonormalColumns(t, "Q", Q)
+			for i := range R.Rows {
+				for j := range min(i, R.Cols) {
+					if R.Data[i*R.Cols+j] != 0 {
+						t.Fatalf("BlockedQR(): R is not upper triangular at (%d, %d)", i, j)
+					}
+				}
+			}
+
+			QR, _ := m.Multiplication(Q, R)
+			for i := range QR.Data {
+				if math.Abs(QR.Data[i]-tt.A.Data[i]) > EPSILON {
+					t.Fatalf("BlockedQR(): QR differs from A at index %d: got %f, want %f", i, QR.Data[i], tt.A.Data[i])
+				}
+			}
+
+			// the reflectors are the same as with one reflector at a time, so the factors match.
+			// qr_Householder also reflects the last column of a square matrix which flips its sign
+			if tt.A.Rows > tt.A.Cols && !tt.thin {
+				wantQ, wantR, _ := qr_Householder(tt.A)
+				for i := range R.Data {
+					if math.Abs(R.Data[i]-wantR.Data[i]) > EPSILON {
+						t.Fatalf("BlockedQR(): R differs from qr_Householder at index %d: got %f, want %f", i, R.Data[i], wantR.Data[i])
+					}
+				}
+				for i := range tt.A.Rows {
+					for j := range tt.A.Cols {
+						if math.Abs(Q.Data[i*Q.Cols+j]-wantQ.Data[i*wantQ.Cols+j]) > EPSILON {
+							t.Fatalf("BlockedQR(): Q differs from qr_Householder at (%d, %d)", i, j)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package qr
+
+import (
+	"fmt"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errFactorSize  = fmt.Errorf("sizes of Q, R and the vectors don't match")
+	errRowOutRange = fmt.Errorf("row index is out of range")
+)
+
+// performs QR decomposition with Givens rotations. The elements below the diagonal are zeroed
+// one at a time from the bottom of each column by rotating neighbouring rows, which only touches
+// two rows per rotation. The factors can be changed afterwards with QRInsertRow, QRDeleteRow
+// and QRRankOneUpdate without decomposing the matrix again
+// returns orthogonal matrix Q (m x m) and upper triangular matrix R (m x n) such that A = QR
+func GivensQR[T m.Float](A m.Dense[T]) (m.Dense[T], m.Dense[T], error) {
+	R := m.Dense[T]{
+		Rows: A.Rows,
+		Cols: A.Cols,
+		Data: make([]T, len(A.Data)),
+	}
+	copy(R.Data, A.Data)
+
+	// the rotations are applied to the rows of Qᵀ so they read contiguous memory
+	QT := m.IdentityOf[T](A.Rows)
+	for j := range min(A.Rows-1, A.Cols) {
+		for i := A.Rows - 1; i > j; i-- {
+			rotateRows(R, QT, i-1, i, j)
+		}
+	}
+
+	return m.Transpose(QT), R, nil
+}
+
+// updates the QR decomposition of A to the decomposition of A with row inserted before row k.
+// the new row is put on top of R, which makes R upper Hessenberg, and its subdiagonal is
+// rotated away. This takes O(m² + mn) work instead of the O(mn²) of a new decomposition
+// returns the new Q and R, or an error if the sizes don't match or k is out of range
+func QRInsertRow[T m.Float](Q, R m.Dense[T], k int, row []T) (m.Dense[T], m.Dense[T], error) {
+	if err := checkFactors(Q, R); err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
+	}
+	if len(row) != R.Cols {
+		return m.Dense[T]{}, m.Dense[T]{}, errFactorSize
+	}
+	if k < 0 || k > Q.Rows {
+		return m.Dense[T]{}, m.Dense[T]{}, errRowOutRange
+	}
+
+	rows := R.Rows + 1
+	newR := m.Dense[T]{
+		Rows: rows,
+		Cols: R.Cols,
+		Data: make([]T, rows*R.Cols),
+	}
+	copy(newR.Row(0), row)
+	copy(newR.Data[R.Cols:], R.Data)
+
+	// with the new row first in R, column i of Qᵀ is the old column i of Qᵀ shifted by one,
+	// and the inserted row k is the first unit vector
+	QT := m.Dense[T]{
+		Rows: rows,
+		Cols: rows,
+		Data: make([]T, rows*rows),
+	}
+	QT.Data[k] = 1
+	for i := range Q.Rows {
+		target := i
+		if i >= k {
+			target++
+		}
+		for j, value := range Q.Row(i) {
+			QT.Data[(j+1)*rows+target] = value
+		}
+	}
+
+	for j := range min(rows-1, R.Cols) {
+		rotateRows(newR, QT, j, j+1, j)
+	}
+
+	return m.Transpose(QT), newR, nil
+}
+
+// updates the QR decomposition of A to the decomposition of A without row k. The row k of Q
+// is rotated to the first unit vector, after which the first column of Q and the first row of R
+// only belong to the removed row and can be dropped
+// returns the new Q and R, or an error if the sizes don't match or k is out of range
+func QRDeleteRow[T m.Float](Q, R m.Dense[T], k int) (m.Dense[T], m.Dense[T], error) {
+	if err := checkFactors(Q, R); err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
+	}
+	if k < 0 || k >= Q.Rows {
+		return m.Dense[T]{}, m.Dense[T]{}, errRowOutRange
+	}
+
+	work := R.View().Copy()
+	QT := m.Transpose(Q)
+	for i := Q.Rows - 1; i > 0; i-- {
+		c, s := givens(QT.Data[(i-1)*QT.Cols+k], QT.Data[i*QT.Cols+k])
+		rotate(QT.Row(i-1), QT.Row(i), c, s)
+		rotate(work.Row(i-1), work.Row(i), c, s)
+	}
+
+	rows := Q.Rows - 1
+	newQ := m.Dense[T]{
+		Rows: rows,
+		Cols: rows,
+		Data: make([]T, rows*rows),
+	}
+	for i := range rows {
+		for j := range rows {
+			source := i
+			if i >= k {
+				source++
+			}
+			newQ.Data[i*rows+j] = QT.Data[(j+1)*QT.Cols+source]
+		}
+	}
+	newR := m.Dense[T]{
+		Rows: rows,
+		Cols: R.Cols,
+		Data: make([]T, rows*R.Cols),
+	}
+	copy(newR.Data, work.Data[R.Cols:])
+
+	return newQ, newR, nil
+}
+
+// updates the QR decomposition of A to the decomposition of A + u * vᵀ. Qᵀu is rotated to a
+// multiple of the first unit vector, which makes R upper Hessenberg, the rank-one change is added
+// to the first row of R and the subdiagonal is rotated away again. This takes O(m² + mn) work
+// returns the new Q and R, or an error if the sizes don't match
+func QRRankOneUpdate[T m.Float](Q, R m.Dense[T], u, v []T) (m.Dense[T], m.Dense[T], error) {
+	if err := checkFactors(Q, R); err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
+	}
+	if len(u) != R.Rows || len(v) != R.Cols {
+		return m.Dense[T]{}, m.Dense[T]{}, errFactorSize
+	}
+
+	newR := R.View().Copy()
+	QT := m.Transpose(Q)
+	w := make([]T, Q.Rows)
+	for i := range w {
+		w[i] = m.Dot(QT.Row(i), u)
+	}
+
+	for i := Q.Rows - 1; i > 0; i-- {
+		c, s := givens(w[i-1], w[i])
+		w[i-1], w[i] = c*w[i-1]-s*w[i], 0
+		rotate(QT.Row(i-1), QT.Row(i), c, s)
+		rotate(newR.Row(i-1), newR.Row(i), c, s)
+	}
+	if Q.Rows > 0 {
+		m.Axpy(w[0], v, newR.Row(0))
+	}
+
+	for j := range min(Q.Rows-1, R.Cols) {
+		rotateRows(newR, QT, j, j+1, j)
+	}
+
+	return m.Transpose(QT), newR, nil
+}
+
+// zeroes R[q, col] by rotating rows p and q of R and Qᵀ. The element is set to exactly zero
+func rotateRows[T m.Float](R, QT m.Dense[T], p, q, col int) {
+	b := R.Data[q*R.Cols+col]
+	if b == 0 {
+		return
+	}
+	c, s := givens(R.Data[p*R.Cols+col], b)
+	rotate(R.Row(p), R.Row(q), c, s)
+	rotate(QT.Row(p), QT.Row(q), c, s)
+	R.Data[q*R.Cols+col] = 0
+}
+
+// checks that Q is square and has as many rows as R
+func checkFactors[T m.Float](Q, R m.Dense[T]) error {
+	if Q.Rows != Q.Cols || Q.Rows != R.Rows {
+		return errFactorSize
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package qr
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+// checks that Q is orthogonal, R is upper triangular and QR equals A
+func checkQR(t *testing.T, function string, Q, R, A m.Matrix) {
+	t.Helper()
+	if Q.Rows != A.Rows || Q.Cols != A.Rows || R.Rows != A.Rows || R.Cols != A.Cols {
+		t.Fatalf("%s(): returned Q %dx%d and R %dx%d for a %dx%d matrix", function, Q.Rows, Q.Cols, R.Rows, R.Cols, A.Rows, A.Cols)
+	}
+	checkOrthonormalColumns(t, "Q", Q)
+	for i := range R.Rows {
+		for j := range min(i, R.Cols) {
+			if math.Abs(R.Data[i*R.Cols+j]) > EPSILON {
+				t.Fatalf("%s(): R is not upper triangular at (%d, %d)", function, i, j)
+			}
+		}
+	}
+	QR, _ := m.Multiplication(Q, R)
+	for i := range QR.Data {
+		if math.Abs(QR.Data[i]-A.Data[i]) > EPSILON {
+			t.Fatalf("%s(): QR differs from A at index %d: got %f, want %f", function, i, QR.Data[i], A.Data[i])
+		}
+	}
+}
+
+// returns A with row inserted before row k
+func insertRow(A m.Matrix, k int, row []float64) m.Matrix {
+	data := make([]float64, 0, len(A.Data)+len(row))
+	data = append(data, A.Data[:k*A.Cols]...)
+	data = append(data, row...)
+	data = append(data, A.Data[k*A.Cols:]...)
+	return m.Matrix{Rows: A.Rows + 1, Cols: A.Cols, Data: data}
+}
+
+func TestGivensQR(t *testing.T) {
+	tests := []struct {
+		name string
+		A    m.Matrix
+	}{
+		{
+			name: "square matrix",
+			A:    m.Matrix{Rows: 3, Cols: 3, Data: []float64{12, -51, 4, 6, 167, -68, -4, 24, -41}},
+		},
+		{
+			name: "tall matrix",
+			A:    createRandomMatrix(9, 4, 1),
+		},
+		{
+			name: "wide matrix",
+			A:    createRandomMatrix(3, 6, 2),
+		},
+		{
+			name: "single row",
+			A:    m.Matrix{Rows: 1, Cols: 2, Data: []float64{3, 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, err := GivensQR(tt.A)
+			if err != nil {
+				t.Fatalf("GivensQR(): returned error %v", err)
+			}
+			checkQR(t, "GivensQR", Q, R, tt.A)
+
+			// R is unique up to the signs of its rows
+			_, wantR, _ := BlockedQR(tt.A, false)
+			for i := range R.Data {
+				if math.Abs(math.Abs(R.Data[i])-math.Abs(wantR.Data[i])) > EPSILON {
+					t.Fatalf("GivensQR(): R differs from BlockedQR at index %d: got %f, want %f", i, R.Data[i], wantR.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestQRInsertRow(t *testing.T) {
+	A := createRandomMatrix(7, 4, 3)
+	row := []float64{0.5, -1, 2, 0.25}
+
+	tests := []struct {
+		name    string
+		A       m.Matrix
+		k       int
+		row     []float64
+		wantErr error
+	}{
+		{name: "first row", A: A, k: 0, row: row},
+		{name: "middle row", A: A, k: 3, row: row},
+		{name: "last row", A: A, k: 7, row: row},
+		{name: "square matrix becomes tall", A: createRandomMatrix(4, 4, 4), k: 2, row: row},
+		{name: "wide matrix", A: createRandomMatrix(2, 4, 5), k: 1, row: row},
+		{name: "index out of range fails", A: A, k: 8, row: row, wantErr: errRowOutRange},
+		{name: "wrong row length fails", A: A, k: 0, row: row[:3], wantErr: errFactorSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, _ := GivensQR(tt.A)
+			newQ, newR, err := QRInsertRow(Q, R, tt.k, tt.row)
+			if err != tt.wantErr {
+				t.Fatalf("QRInsertRow(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			checkQR(t, "QRInsertRow", newQ, newR, insertRow(tt.A, tt.k, tt.row))
+		})
+	}
+}
+
+func TestQRDeleteRow(t *testing.T) {
+	A := createRandomMatrix(7, 4, 6)
+
+	tests := []struct {
+		name    string
+		A       m.Matrix
+		k       int
+		wantErr error
+	}{
+		{name: "first row", A: A, k: 0},
+		{name: "middle row", A: A, k: 4},
+		{name: "last row", A: A, k: 6},
+		{name: "tall matrix becomes square", A: createRandomMatrix(5, 4, 7), k: 1},
+		{name: "wide matrix", A: createRandomMatrix(3, 5, 8), k: 2},
+		{name: "index out of range fails", A: A, k: 7, wantErr: errRowOutRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, _ := GivensQR(tt.A)
+			newQ, newR, err := QRDeleteRow(Q, R, tt.k)
+			if err != tt.wantErr {
+				t.Fatalf("QRDeleteRow(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			want := m.Matrix{Rows: tt.A.Rows - 1, Cols: tt.A.Cols}
+			want.Data = append(append(want.Data, tt.A.Data[:tt.k*tt.A.Cols]...), tt.A.Data[(tt.k+1)*tt.A.Cols:]...)
+			checkQR(t, "QRDeleteRow", newQ, newR, want)
+		})
+	}
+}
+
+func TestQRRankOneUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		A       m.Matrix
+		u       []float64
+		v       []float64
+		wantErr error
+	}{
+		{
+			name: "tall matrix",
+			A:    createRandomMatrix(6, 3, 9),
+			u:    []float64{1, -2, 0.5, 0, 3, 1},
+			v:    []float64{0.5, 1, -1},
+		},
+		{
+			name: "update makes the matrix singular",
+			A:    m.Identity(3),
+			u:    []float64{-1, 0, 0},
+			v:    []float64{1, 0, 0},
+		},
+		{
+			name: "wide matrix",
+			A:    createRandomMatrix(2, 4, 10),
+			u:    []float64{1, 2},
+			v:    []float64{1, 0, -1, 2},
+		},
+		{
+			name:    "wrong vector length fails",
+			A:       m.Identity(3),
+			u:       []float64{1, 2},
+			v:       []float64{1, 2, 3},
+			wantErr: errFactorSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Q, R, _ := GivensQR(tt.A)
+			newQ, newR, err := QRRankOneUpdate(Q, R, tt.u, tt.v)
+			if err != tt.wantErr {
+				t.Fatalf("QRRankOneUpdate(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			want := tt.A.View().Copy()
+			for i := range want.Rows {
+				m.Axpy(tt.u[i], tt.v, want.Row(i))
+			}
+			checkQR(t, "QRRankOneUpdate", newQ, newR, want)
+		})
+	}
+}
+
+func TestCheckFactors(t *testing.T) {
+	Q, R, _ := GivensQR(createRandomMatrix(4, 3, 11))
+	if _, _, err := QRDeleteRow(m.Identity(3), R, 0); err != errFactorSize {
+		t.Errorf("QRDeleteRow(): returned wrong error %v for mismatched factors", err)
+	}
+	if _, _, err := QRRankOneUpdate(Q, Q, make([]float64, 4), make([]float64, 3)); err != errFactorSize {
+		t.Errorf("QRRankOneUpdate(): returned wrong error %v for a wrong vector length", err)
+	}
+}
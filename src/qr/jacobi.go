@@ -0,0 +1,190 @@
+package qr
+
+import (
+	"cmp"
+	"math"
+	"runtime"
+	"sync"
+
+	m "face_recognition/matrix"
+)
+
+// maximum number of sweeps over all off-diagonal pairs. Jacobi converges quadratically
+// so well conditioned matrices usually need under 10
+const maxJacobiSweeps = 100
+
+// matrices smaller than this are rotated on a single goroutine
+const parallelJacobiSize = 128
+
+// computes the eigenvalues and eigenvectors of a symmetric matrix with the cyclic Jacobi method.
+// every step zeroes one off-diagonal pair with a rotation until the matrix is diagonal. The
+// rotations are applied directly to the eigenvectors so they stay orthogonal to working precision,
+// and the eigenvalues have high relative accuracy. The pairs are visited in round-robin order where
+// each round has n/2 disjoint pairs, so the rotations of a round can run in parallel.
+// It is slower than SymmetricQR and is mainly used as a reference to test the other solvers against
+// returns the eigenvalues and a matrix with the corresponding eigenvectors as columns in the same
+// format as QR_algorithm, or an error if the matrix isn't square or the iteration doesn't converge
+func Jacobi[T m.Float](A m.Dense[T]) ([]T, m.Dense[T], error) {
+	values, vectors, _, err := jacobi(A, Options{})
+	return values, vectors, err
+}
+
+// runs the Jacobi method with the tolerance and sweep limit of opts. A pair is rotated while
+// its off-diagonal element is larger than opts.Tolerance relative to the diagonal elements
+// returns the eigenvalues, the eigenvectors and the number of sweeps
+func jacobi[T m.Float](A m.Dense[T], opts Options) ([]T, m.Dense[T], int, error) {
+	if A.Rows != A.Cols {
+		return nil, m.Dense[T]{}, 0, errNotSquare
+	}
+	maxSweeps := cmp.Or(opts.MaxIterations, maxJacobiSweeps)
+
+	n := A.Rows
+	work := m.Dense[T]{
+		Rows: n,
+		Cols: n,
+		Data: make([]T, n*n),
+	}
+	copy(work.Data, A.Data)
+	VT := m.IdentityOf[T](n)
+
+	rounds := roundRobin(n)
+	tolerance := T(cmp.Or(opts.Tolerance, m.Epsilon[T]()))
+	rotations := make([]jacobiRotation[T], 0, (n+1)/2)
+
+	converged := n < 2
+	sweeps := 0
+	for !converged && sweeps < maxSweeps {
+		sweeps++
+
+		rotated := false
+		for _, pairs := range rounds {
+			rotations = rotations[:0]
+			for _, pair := range pairs {
+				p, q := pair[0], pair[1]
+				apq := work.Data[p*n+q]
+				app := work.Data[p*n+p]
+				aqq := work.Data[q*n+q]
+				if apq == 0 || m.Abs(apq) <= tolerance*T(math.Sqrt(float64(m.Abs(app*aqq)))) {
+					continue
+				}
+
+				// symmetric Schur decomposition of the 2x2 block
+				tau := (aqq - app) / (2 * apq)
+				t := 1 / (m.Abs(tau) + T(math.Sqrt(float64(1+tau*tau))))
+				if tau < 0 {
+					t = -t
+				}
+				c := 1 / T(math.Sqrt(float64(1+t*t)))
+				rotations = append(rotations, jacobiRotation[T]{p: p, q: q, c: c, s: t * c})
+			}
+			if len(rotations) == 0 {
+				continue
+			}
+			rotated = true
+			applyRotations(work, VT, rotations)
+		}
+
+		converged = !rotated
+	}
+	if !converged {
+		return nil, m.Dense[T]{}, sweeps, &NotConvergedError{Method: "Jacobi", Iterations: sweeps}
+	}
+
+	values := make([]T, n)
+	for i := range n {
+		values[i] = work.Data[i*n+i]
+	}
+
+	return values, m.Transpose(VT), sweeps, nil
+}
+
+// rotation of rows and columns p and q
+type jacobiRotation[T m.Float] struct {
+	p, q int
+	c, s T
+}
+
+// applies disjoint rotations as A = JᵀAJ and Vᵀ = JᵀVᵀ. The rows are rotated pair by pair
+// and the columns row by row, so both loops split between goroutines without locking
+func applyRotations[T m.Float](A, VT m.Dense[T], rotations []jacobiRotation[T]) {
+	n := A.Rows
+	parallelFor(len(rotations), n, func(start, end int) {
+		for _, r := range rotations[start:end] {
+			rotate(A.Row(r.p), A.Row(r.q), r.c, r.s)
+			rotate(VT.Row(r.p), VT.Row(r.q), r.c, r.s)
+		}
+	})
+	parallelFor(n, n, func(start, end int) {
+		for i := start; i < end; i++ {
+			row := A.Row(i)
+			for _, r := range rotations {
+				x, y := row[r.p], row[r.q]
+				row[r.p] = r.c*x - r.s*y
+				row[r.q] = r.s*x + r.c*y
+			}
+		}
+	})
+
+	// the rotated pairs are zero up to rounding
+	for _, r := range rotations {
+		A.Data[r.p*n+r.q] = 0
+		A.Data[r.q*n+r.p] = 0
+	}
+}
+
+// runs fn over [0, count) split into ranges for each processor when the matrix size n is large
+func parallelFor(count, n int, fn func(start, end int)) {
+	workers := 1
+	if n >= parallelJacobiSize {
+		workers = max(1, min(runtime.GOMAXPROCS(0), count))
+	}
+	if workers == 1 {
+		fn(0, count)
+		return
+	}
+
+	chunk := (count + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < count; start += chunk {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			fn(start, end)
+		}(start, min(start+chunk, count))
+	}
+	wg.Wait()
+}
+
+// returns the rounds of a round-robin tournament of n players. Every pair meets once
+// and no index appears twice in the same round. With odd n one player sits out each round
+func roundRobin(n int) [][][2]int {
+	players := n
+	if players%2 == 1 {
+		players++
+	}
+
+	order := make([]int, players)
+	for i := range order {
+		order[i] = i
+	}
+
+	rounds := make([][][2]int, 0, players-1)
+	for range players - 1 {
+		var pairs [][2]int
+		for i := range players / 2 {
+			p, q := order[i], order[players-1-i]
+			if p >= n || q >= n {
+				continue
+			}
+			pairs = append(pairs, [2]int{min(p, q), max(p, q)})
+		}
+		rounds = append(rounds, pairs)
+
+		// the first player stays in place and the rest rotate by one
+		last := order[players-1]
+		copy(order[2:], order[1:players-1])
+		order[1] = last
+	}
+
+	return rounds
+}
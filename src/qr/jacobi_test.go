@@ -0,0 +1,157 @@
+package qr
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestJacobi(t *testing.T) {
+	tests := []struct {
+		name       string
+		A          m.Matrix
+		wantValues []float64
+		wantErr    error
+	}{
+		{
+			name:       "known eigenvalues",
+			A:          m.Matrix{Rows: 3, Cols: 3, Data: []float64{2, -1, 0, -1, 2, -1, 0, -1, 2}},
+			wantValues: []float64{2 + math.Sqrt2, 2, 2 - math.Sqrt2},
+		},
+		{
+			name:       "indefinite matrix with zero diagonal",
+			A:          m.Matrix{Rows: 2, Cols: 2, Data: []float64{0, 1, 1, 0}},
+			wantValues: []float64{1, -1},
+		},
+		{
+			name:       "odd size",
+			A:          m.Matrix{Rows: 5, Cols: 5, Data: []float64{5, 0, 0, 0, 1, 0, 4, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 2, 0, 1, 0, 0, 0, 1}},
+			wantValues: []float64{5.236068, 4, 3, 2, 0.763932},
+		},
+		{
+			name: "large covariance uses the parallel rotations",
+			A:    m.Gram(createRandomMatrix(200, 150, 4)),
+		},
+		{
+			name:    "non-square matrix fails",
+			A:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{1, 2}},
+			wantErr: errNotSquare,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, vectors, err := Jacobi(tt.A)
+			if err != tt.wantErr {
+				t.Fatalf("Jacobi(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			sorted := slices.Clone(values)
+			slices.Sort(sorted)
+			slices.Reverse(sorted)
+			for i := range tt.wantValues {
+				if math.Abs(sorted[i]-tt.wantValues[i]) > EPSILON {
+					t.Errorf("Jacobi(): eigenvalue %d is %f, want %f", i, sorted[i], tt.wantValues[i])
+				}
+			}
+
+			n := tt.A.Rows
+			AV, _ := m.Multiplication(tt.A, vectors)
+			scale := math.Max(1, slices.Max(sorted))
+			for i := range n {
+				for j := range n {
+					if math.Abs(AV.Data[i*n+j]-values[j]*vectors.Data[i*n+j]) > EPSILON*scale {
+						t.Fatalf("Jacobi(): Av differs from λv for eigenvalue %d", j)
+					}
+				}
+			}
+			checkOrthonormalColumns(t, "eigenvectors", vectors)
+		})
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	for _, n := range []int{2, 5, 8} {
+		seen := make(map[[2]int]bool)
+		for _, pairs := range roundRobin(n) {
+			used := make(map[int]bool)
+			for _, pair := range pairs {
+				if used[pair[0]] || used[pair[1]] {
+					t.Errorf("roundRobin(%d): index used twice in a round: %v", n, pairs)
+				}
+				used[pair[0]], used[pair[1]] = true, true
+				if seen[pair] {
+					t.Errorf("roundRobin(%d): pair %v appears twice", n, pair)
+				}
+				seen[pair] = true
+			}
+		}
+		if len(seen) != n*(n-1)/2 {
+			t.Errorf("roundRobin(%d): got %d pairs, want %d", n, len(seen), n*(n-1)/2)
+		}
+	}
+}
+
+// the Jacobi method is the reference for the other eigenvalue solvers
+func TestSolversMatchJacobi(t *testing.T) {
+	data := createRandomMatrix(60, 12, 5)
+	A := m.Gram(data)
+
+	wantValues, wantVectors, err := Jacobi(A)
+	if err != nil {
+		t.Fatalf("Jacobi(): returned error %v", err)
+	}
+	wantVectors = m.SortEigenvectors(wantValues, wantVectors)
+	slices.Sort(wantValues)
+	slices.Reverse(wantValues)
+
+	solvers := []struct {
+		name      string
+		solve     func(m.Matrix) ([]float64, m.Matrix, error)
+		tolerance float64
+	}{
+		{name: "SymmetricQR", solve: SymmetricQR[float64], tolerance: EPSILON},
+		{name: "QR_algorithm", solve: QR_algorithm[float64], tolerance: 1e-3},
+		{name: "SVD", solve: func(A m.Matrix) ([]float64, m.Matrix, error) {
+			// the right singular vectors of the data are the eigenvectors of its covariance
+			_, S, VT, err := SVD(data, true)
+			for i := range S {
+				S[i] *= S[i]
+			}
+			return S, m.Transpose(VT), err
+		}, tolerance: EPSILON},
+	}
+
+	for _, solver := range solvers {
+		t.Run(solver.name, func(t *testing.T) {
+			values, vectors, err := solver.solve(A)
+			if err != nil {
+				t.Fatalf("%s(): returned error %v", solver.name, err)
+			}
+			vectors = m.SortEigenvectors(values, vectors)
+			slices.Sort(values)
+			slices.Reverse(values)
+
+			n := A.Rows
+			for j := range n {
+				if math.Abs(values[j]-wantValues[j]) > solver.tolerance*wantValues[0] {
+					t.Errorf("%s(): eigenvalue %d is %f, Jacobi gives %f", solver.name, j, values[j], wantValues[j])
+				}
+
+				// eigenvectors are unique up to their sign
+				dot := 0.0
+				for i := range n {
+					dot += vectors.Data[i*n+j] * wantVectors.Data[i*n+j]
+				}
+				if math.Abs(math.Abs(dot)-1) > solver.tolerance {
+					t.Errorf("%s(): eigenvector %d differs from Jacobi, |dot| is %f", solver.name, j, math.Abs(dot))
+				}
+			}
+		})
+	}
+}
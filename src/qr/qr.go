@@ -1,6 +1,7 @@
 package qr
 
 import (
+	"cmp"
 	"math"
 	"slices"
 
@@ -11,15 +12,15 @@ import (
 
 // computes the householder reflection vector and its corresponding beta value for a given column of matrix R
 // returns the beta value used in the Householder transformation
-func calculateHouseholderVector(R m.Matrix, colIdx, size int, householderVector []float64) (float64, error) {
-	norm := 0.0
+func calculateHouseholderVector[T m.Float](R m.Dense[T], colIdx, size int, householderVector []T) (T, error) {
+	var norm T = 0
 	for i := range size {
 		val := R.Data[(colIdx+i)*R.Cols+colIdx]
 		householderVector[i] = val
 		norm += val * val
 	}
 
-	norm = math.Sqrt(norm)
+	norm = T(math.Sqrt(float64(norm)))
 
 	if householderVector[0] >= 0 {
 		householderVector[0] += norm
@@ -27,7 +28,7 @@ func calculateHouseholderVector(R m.Matrix, colIdx, size int, householderVector
 		householderVector[0] -= norm
 	}
 
-	norm_sq := 0.0
+	var norm_sq T = 0
 	for i := range size {
 		norm_sq += householderVector[i] * householderVector[i]
 	}
@@ -37,9 +38,9 @@ func calculateHouseholderVector(R m.Matrix, colIdx, size int, householderVector
 
 // applies the Householder transformation to update the R matrix
 // using the computed Householdr vector and beta value
-func updateRMatrix(R m.Matrix, colIdx, size int, householderVector []float64, beta float64) {
+func updateRMatrix[T m.Float](R m.Dense[T], colIdx, size int, householderVector []T, beta T) {
 	for col := colIdx; col < R.Cols; col++ {
-		dotProduct := 0.0
+		var dotProduct T = 0
 		for i := range size {
 			dotProduct += householderVector[i] * R.Data[(colIdx+i)*R.Cols+col]
 		}
@@ -52,9 +53,9 @@ func updateRMatrix(R m.Matrix, colIdx, size int, householderVector []float64, be
 
 // applies the Householder transformation to update the Q matrix
 // using the computed Householder vector and beta value
-func updateQMatrix(Q m.Matrix, colIdx, size int, householderVector []float64, beta float64) {
+func updateQMatrix[T m.Float](Q m.Dense[T], colIdx, size int, householderVector []T, beta T) {
 	for rowIndex := range Q.Rows {
-		dotProduct := 0.0
+		var dotProduct T = 0
 		for i := range size {
 			dotProduct += Q.Data[rowIndex*Q.Rows+(colIdx+i)] * householderVector[i]
 		}
@@ -67,18 +68,18 @@ func updateQMatrix(Q m.Matrix, colIdx, size int, householderVector []float64, be
 
 // performs QR decomposition using Householder reflections
 // returns orthogonal matrix Q and upper triangular matrix R such that A = QR
-func qr_Householder(A m.Matrix) (m.Matrix, m.Matrix, error) {
+func qr_Householder[T m.Float](A m.Dense[T]) (m.Dense[T], m.Dense[T], error) {
 	rows := A.Rows
 	cols := A.Cols
 
-	Q := m.Identity(rows)
-	R := m.Matrix{
+	Q := m.IdentityOf[T](rows)
+	R := m.Dense[T]{
 		Rows: rows,
 		Cols: cols,
 		Data: slices.Clone(A.Data),
 	}
 
-	householderStorage := make([]float64, rows)
+	householderStorage := make([]T, rows)
 
 	for colIdx := range cols {
 		size := rows - colIdx
@@ -86,7 +87,7 @@ func qr_Householder(A m.Matrix) (m.Matrix, m.Matrix, error) {
 
 		beta, err := calculateHouseholderVector(R, colIdx, size, householderVector)
 		if err != nil {
-			return m.Matrix{}, m.Matrix{}, err
+			return m.Dense[T]{}, m.Dense[T]{}, err
 		}
 
 		updateRMatrix(R, colIdx, size, householderVector, beta)
@@ -95,60 +96,91 @@ func qr_Householder(A m.Matrix) (m.Matrix, m.Matrix, error) {
 	return Q, R, nil
 }
 
+// default iteration limit and tolerance of the QR algorithm
+const (
+	maxQRIterations = 1000
+	qrTolerance     = 10e-8
+)
+
 // QR algorithm to find eigenvalues and eigenvectors of a matrix
-// returns a slice of eigenvalues and a matrix of the corresponding eigenvectors
-func QR_algorithm(A m.Matrix) ([]float64, m.Matrix, error) {
-	currentMatrix := m.Matrix{
+// returns a slice of eigenvalues and a matrix of the corresponding eigenvectors,
+// or a NotConvergedError if the diagonal doesn't settle in 1000 iterations
+func QR_algorithm[T m.Float](A m.Dense[T]) ([]T, m.Dense[T], error) {
+	values, vectors, _, err := qrAlgorithm(A, Options{})
+	return values, vectors, err
+}
+
+// runs the QR algorithm until no diagonal element changes more than opts.Tolerance between
+// two iterations or opts.MaxIterations is reached
+// returns the eigenvalues, the eigenvectors and the number of iterations
+func qrAlgorithm[T m.Float](A m.Dense[T], opts Options) ([]T, m.Dense[T], int, error) {
+	maxIter := cmp.Or(opts.MaxIterations, maxQRIterations)
+	tolerance := cmp.Or(opts.Tolerance, qrTolerance)
+
+	currentMatrix := m.Dense[T]{
 		Rows: A.Rows,
 		Cols: A.Cols,
 		Data: slices.Clone(A.Data),
 	}
 
-	eigenvectorMatrix := m.Identity(A.Rows)
+	eigenvectorMatrix := m.IdentityOf[T](A.Rows)
 
-	maxIter := 1000
-	for range maxIter {
+	// buffers for the next iteration are swapped with the current ones to avoid allocations
+	nextMatrix := m.Dense[T]{
+		Rows: A.Rows,
+		Cols: A.Cols,
+		Data: make([]T, len(A.Data)),
+	}
+	nextEigenvectorMatrix := m.Dense[T]{
+		Rows: A.Rows,
+		Cols: A.Rows,
+		Data: make([]T, A.Rows*A.Rows),
+	}
+
+	for iteration := range maxIter {
 		Q, R, err := qr_Householder(currentMatrix)
 		if err != nil {
-			return nil, m.Matrix{}, err
+			return nil, m.Dense[T]{}, 0, err
 		}
 
-		nextMatrix, err := m.Multiplication(R, Q)
-		if err != nil {
-			return nil, m.Matrix{}, err
+		if err := m.MultiplicationInto(nextMatrix, R, Q); err != nil {
+			return nil, m.Dense[T]{}, 0, err
 		}
 
-		newEigenvectorMatrix, err := m.Multiplication(eigenvectorMatrix, Q)
-		if err != nil {
-			return nil, m.Matrix{}, err
+		if err := m.MultiplicationInto(nextEigenvectorMatrix, eigenvectorMatrix, Q); err != nil {
+			return nil, m.Dense[T]{}, 0, err
 		}
-		eigenvectorMatrix = newEigenvectorMatrix
+		eigenvectorMatrix, nextEigenvectorMatrix = nextEigenvectorMatrix, eigenvectorMatrix
 
-		if hasConverged(currentMatrix, nextMatrix) {
+		if hasConverged(currentMatrix, nextMatrix, tolerance) {
 			n := A.Rows
-			eigenValues := make([]float64, n)
+			eigenValues := make([]T, n)
 			for i := range n {
 				eigenValues[i] = nextMatrix.Data[i*nextMatrix.Cols+i]
 			}
-			return eigenValues, eigenvectorMatrix, nil
+			return eigenValues, eigenvectorMatrix, iteration + 1, nil
 		}
 
-		currentMatrix = nextMatrix
+		currentMatrix, nextMatrix = nextMatrix, currentMatrix
 	}
 
-	n := A.Rows
-	eigenValues := make([]float64, n)
-	for i := range n {
-		eigenValues[i] = currentMatrix.Data[i*currentMatrix.Cols+i]
-	}
-	return eigenValues, eigenvectorMatrix, nil
+	return nil, m.Dense[T]{}, maxIter, &NotConvergedError{Method: "QR algorithm", Iterations: maxIter}
 }
 
+// relative tolerance of the convergence check in single precision. float32 only has about
+// 7 significant digits so the absolute tolerance used with float64 is never reached
+const float32Tolerance = 1e-6
+
 // checks if the QR algorithm has converged by comparing the diagonal elements of two consecutive iterations
-func hasConverged(prev, curr m.Matrix) bool {
-	tol := 10e-8
+func hasConverged[T m.Float](prev, curr m.Dense[T], tol float64) bool {
+	relative := 0.0
+	if _, ok := any(T(0)).(float32); ok {
+		relative = float32Tolerance
+	}
+
 	for i := range prev.Rows {
-		if math.Abs(prev.Data[i*prev.Cols+i]-curr.Data[i*curr.Cols+i]) > tol {
+		value := float64(curr.Data[i*curr.Cols+i])
+		if math.Abs(float64(prev.Data[i*prev.Cols+i])-value) > max(tol, relative*math.Abs(value)) {
 			return false
 		}
 	}
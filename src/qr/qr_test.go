@@ -404,6 +404,36 @@ func TestQR_algorithm(t *testing.T) {
 	}
 }
 
+func TestQR_algorithmFloat32(t *testing.T) {
+	// single precision has about 7 significant digits. The iteration stops when the eigenvalues
+	// settle, and the eigenvectors lag behind them, so they get a looser tolerance
+	const float32Epsilon = 1e-4
+	const vectorEpsilon = 1e-3
+
+	A := m.Matrix{
+		Rows: 3,
+		Cols: 3,
+		Data: []float64{4, 1, 0.5, 1, 3, 0.2, 0.5, 0.2, 1},
+	}
+
+	wantValues, wantVectors, _ := QR_algorithm(A)
+	values, vectors, err := QR_algorithm(m.Convert[float32](A))
+	if err != nil {
+		t.Fatalf("QR_algorithm(): returned error: %v", err)
+	}
+
+	for i := range wantValues {
+		if math.Abs(float64(values[i])-wantValues[i]) > float32Epsilon*math.Abs(wantValues[i]) {
+			t.Errorf("QR_algorithm(): float32 eigenvalue %d is %f, float64 is %f", i, values[i], wantValues[i])
+		}
+	}
+	for i := range wantVectors.Data {
+		if math.Abs(float64(vectors.Data[i])-wantVectors.Data[i]) > vectorEpsilon {
+			t.Errorf("QR_algorithm(): float32 eigenvector at index %d is %f, float64 is %f", i, vectors.Data[i], wantVectors.Data[i])
+		}
+	}
+}
+
 func TestHasConverged(t *testing.T) {
 	tests := []struct {
 		name string
@@ -477,7 +507,7 @@ func TestHasConverged(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := hasConverged(tt.prev, tt.curr)
+			result := hasConverged(tt.prev, tt.curr, qrTolerance)
 			if result != tt.want {
 				t.Errorf("hasConverged(): %v, want %v", result, tt.want)
 			}
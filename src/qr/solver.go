@@ -0,0 +1,161 @@
+package qr
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+
+	m "face_recognition/matrix"
+)
+
+// settings of an eigen solver. The zero value uses the defaults of the solver
+type Options struct {
+	Tolerance     float64 // convergence tolerance, see the solver for what it is compared to
+	MaxIterations int     // maximum number of iterations, see the solver for what one iteration is
+	Count         int     // number of largest eigenpairs to return, 0 returns all of them
+}
+
+// eigenpairs computed by an eigen solver with diagnostics of how accurate they are
+type Result[T m.Float] struct {
+	Values        []T        // eigenvalues in descending order, equal ones in the order the solver found them
+	Vectors       m.Dense[T] // matrix with the corresponding eigenvectors as columns with canonical signs
+	Iterations    int        // number of iterations the solver took
+	Residuals     []float64  // ‖Av − λv‖ of every eigenpair
+	Orthogonality float64    // largest absolute element of VᵀV − I
+}
+
+// EigenSolver computes the largest eigenpairs of a symmetric matrix
+type EigenSolver[T m.Float] interface {
+	Solve(A m.Dense[T], opts Options) (Result[T], error)
+}
+
+// NotConvergedError is returned when a solver reaches its iteration limit
+type NotConvergedError struct {
+	Method     string // name of the solver
+	Iterations int    // number of iterations taken
+}
+
+func (e *NotConvergedError) Error() string {
+	return fmt.Sprintf("%s didn't converge in %d iterations", e.Method, e.Iterations)
+}
+
+// solves all eigenpairs with QR_algorithm. One iteration is one QR step and the tolerance
+// is the largest change of a diagonal element between two steps
+type QRSolver[T m.Float] struct{}
+
+func (QRSolver[T]) Solve(A m.Dense[T], opts Options) (Result[T], error) {
+	values, vectors, iterations, err := qrAlgorithm(A, opts)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return newResult(A, values, vectors, iterations, opts.Count)
+}
+
+// solves all eigenpairs with SymmetricQR. One iteration is one implicit QR step and the
+// tolerance is the size of a negligible off-diagonal element relative to its neighbours
+type SymmetricSolver[T m.Float] struct{}
+
+func (SymmetricSolver[T]) Solve(A m.Dense[T], opts Options) (Result[T], error) {
+	values, vectors, iterations, err := symmetricQR(A, opts)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return newResult(A, values, vectors, iterations, opts.Count)
+}
+
+// solves all eigenpairs with Jacobi. One iteration is one sweep over all pairs and the
+// tolerance is the size of a negligible off-diagonal element relative to the diagonal
+type JacobiSolver[T m.Float] struct{}
+
+func (JacobiSolver[T]) Solve(A m.Dense[T], opts Options) (Result[T], error) {
+	values, vectors, iterations, err := jacobi(A, opts)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return newResult(A, values, vectors, iterations, opts.Count)
+}
+
+// solves the largest eigenpairs with Lanczos. One iteration is one basis vector and the
+// tolerance is the largest residual relative to the largest eigenvalue
+type LanczosSolver[T m.Float] struct{}
+
+func (LanczosSolver[T]) Solve(A m.Dense[T], opts Options) (Result[T], error) {
+	values, vectors, iterations, err := lanczos(A, cmp.Or(opts.Count, A.Rows), opts)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return newResult(A, values, vectors, iterations, opts.Count)
+}
+
+// solves the largest eigenpairs with SubspaceIteration. One iteration is one multiplication
+// of the block and the tolerance is the largest residual relative to the largest eigenvalue
+type SubspaceSolver[T m.Float] struct{}
+
+func (SubspaceSolver[T]) Solve(A m.Dense[T], opts Options) (Result[T], error) {
+	values, vectors, iterations, err := subspaceIteration(A, cmp.Or(opts.Count, A.Rows), opts)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return newResult(A, values, vectors, iterations, opts.Count)
+}
+
+// sorts the eigenpairs in descending order, keeps the first count of them, fixes the signs of the
+// eigenvectors with m.CanonicalSigns and computes their residuals and the orthogonality error
+// returns the result or an error if count is invalid
+func newResult[T m.Float](A m.Dense[T], values []T, vectors m.Dense[T], iterations, count int) (Result[T], error) {
+	if count < 0 || count > len(values) {
+		return Result[T]{}, errInvalidK
+	}
+	if count == 0 {
+		count = len(values)
+	}
+
+	n := vectors.Rows
+	result := Result[T]{
+		Values: make([]T, count),
+		Vectors: m.Dense[T]{
+			Rows: n,
+			Cols: count,
+			Data: make([]T, n*count),
+		},
+		Iterations: iterations,
+		Residuals:  make([]float64, count),
+	}
+	for col, i := range descendingOrder(values)[:count] {
+		result.Values[col] = values[i]
+		for row := range n {
+			result.Vectors.Data[row*count+col] = vectors.Data[row*vectors.Cols+i]
+		}
+	}
+	m.CanonicalSigns(result.Vectors)
+
+	AV, err := m.Multiplication(A, result.Vectors)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	for row := range n {
+		for col, value := range result.Values {
+			diff := float64(AV.Data[row*count+col] - value*result.Vectors.Data[row*count+col])
+			result.Residuals[col] += diff * diff
+		}
+	}
+	for col := range result.Residuals {
+		result.Residuals[col] = math.Sqrt(result.Residuals[col])
+	}
+
+	VTV, err := m.MulTransA(result.Vectors, result.Vectors)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	for i := range count {
+		for j := range count {
+			want := 0.0
+			if i == j {
+				want = 1
+			}
+			result.Orthogonality = max(result.Orthogonality, math.Abs(float64(VTV.Data[i*count+j])-want))
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,175 @@
+package qr
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestEigenSolvers(t *testing.T) {
+	decaying := decayingValues(30, 100, 0.7)
+	A := createMatrixWithSpectrum(30, 30, decaying, true)
+
+	solvers := []struct {
+		name   string
+		solver EigenSolver[float64]
+	}{
+		{name: "QRSolver", solver: QRSolver[float64]{}},
+		{name: "SymmetricSolver", solver: SymmetricSolver[float64]{}},
+		{name: "JacobiSolver", solver: JacobiSolver[float64]{}},
+		{name: "LanczosSolver", solver: LanczosSolver[float64]{}},
+		{name: "SubspaceSolver", solver: SubspaceSolver[float64]{}},
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:      "all eigenpairs",
+			opts:      Options{},
+			wantCount: 30,
+		},
+		{
+			name:      "largest eigenpairs",
+			opts:      Options{Count: 4},
+			wantCount: 4,
+		},
+		{
+			name:    "too large count fails",
+			opts:    Options{Count: 31},
+			wantErr: errInvalidK,
+		},
+	}
+
+	for _, solver := range solvers {
+		for _, tt := range tests {
+			t.Run(solver.name+"/"+tt.name, func(t *testing.T) {
+				result, err := solver.solver.Solve(A, tt.opts)
+				if err != tt.wantErr {
+					t.Fatalf("%s.Solve(): returned wrong error %v, want %v", solver.name, err, tt.wantErr)
+				}
+				if err != nil {
+					return
+				}
+
+				if len(result.Values) != tt.wantCount || result.Vectors.Cols != tt.wantCount || len(result.Residuals) != tt.wantCount {
+					t.Fatalf("%s.Solve(): returned %d eigenpairs, want %d", solver.name, len(result.Values), tt.wantCount)
+				}
+				if result.Iterations < 1 {
+					t.Errorf("%s.Solve(): reported %d iterations", solver.name, result.Iterations)
+				}
+				// the unshifted QR algorithm stops when the diagonal settles, long before the vectors have
+				tolerance := EPSILON
+				if solver.name == "QRSolver" {
+					tolerance = 1e-2
+				}
+				for i, value := range result.Values {
+					if math.Abs(value-decaying[i]) > tolerance {
+						t.Errorf("%s.Solve(): eigenvalue %d is %f, want %f", solver.name, i, value, decaying[i])
+					}
+					if result.Residuals[i] > tolerance*decaying[0] {
+						t.Errorf("%s.Solve(): residual %d is %g", solver.name, i, result.Residuals[i])
+					}
+				}
+				if result.Orthogonality > EPSILON {
+					t.Errorf("%s.Solve(): orthogonality error is %g", solver.name, result.Orthogonality)
+				}
+			})
+		}
+	}
+}
+
+func TestEigenSolversNotConverged(t *testing.T) {
+	A := createMatrixWithSpectrum(30, 30, decayingValues(30, 100, 0.99), true)
+
+	solvers := []struct {
+		name       string
+		solver     EigenSolver[float64]
+		opts       Options
+		wantMethod string
+	}{
+		{name: "QRSolver", solver: QRSolver[float64]{}, opts: Options{MaxIterations: 2}, wantMethod: "QR algorithm"},
+		{name: "SymmetricSolver", solver: SymmetricSolver[float64]{}, opts: Options{MaxIterations: 2}, wantMethod: "symmetric QR"},
+		{name: "JacobiSolver", solver: JacobiSolver[float64]{}, opts: Options{MaxIterations: 2}, wantMethod: "Jacobi"},
+		{name: "LanczosSolver", solver: LanczosSolver[float64]{}, opts: Options{MaxIterations: 5, Count: 3}, wantMethod: "Lanczos"},
+		{name: "SubspaceSolver", solver: SubspaceSolver[float64]{}, opts: Options{MaxIterations: 2, Count: 3}, wantMethod: "subspace iteration"},
+	}
+
+	for _, tt := range solvers {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.solver.Solve(A, tt.opts)
+
+			var notConverged *NotConvergedError
+			if !errors.As(err, &notConverged) {
+				t.Fatalf("%s.Solve(): returned error %v, want NotConvergedError", tt.name, err)
+			}
+			if notConverged.Method != tt.wantMethod {
+				t.Errorf("%s.Solve(): error reports method %q, want %q", tt.name, notConverged.Method, tt.wantMethod)
+			}
+			if notConverged.Iterations != tt.opts.MaxIterations {
+				t.Errorf("%s.Solve(): error reports %d iterations, want %d", tt.name, notConverged.Iterations, tt.opts.MaxIterations)
+			}
+		})
+	}
+}
+
+func TestEigenSolversFloat32(t *testing.T) {
+	A := m.Convert[float32](createMatrixWithSpectrum(10, 10, decayingValues(10, 10, 0.5), true))
+
+	result, err := SymmetricSolver[float32]{}.Solve(A, Options{Count: 3})
+	if err != nil {
+		t.Fatalf("SymmetricSolver.Solve(): returned error %v", err)
+	}
+	if result.Orthogonality > 1e-5 || result.Residuals[0] > 1e-4 {
+		t.Errorf("SymmetricSolver.Solve(): orthogonality error %g and residual %g are too large", result.Orthogonality, result.Residuals[0])
+	}
+}
+
+func TestEigenSolversCanonicalSigns(t *testing.T) {
+	data := createRandomMatrix(40, 8, 12)
+	A := m.Gram(data)
+
+	want, err := JacobiSolver[float64]{}.Solve(A, Options{})
+	if err != nil {
+		t.Fatalf("JacobiSolver.Solve(): returned error %v", err)
+	}
+
+	solvers := []struct {
+		name   string
+		solver EigenSolver[float64]
+	}{
+		{name: "SymmetricSolver", solver: SymmetricSolver[float64]{}},
+		{name: "LanczosSolver", solver: LanczosSolver[float64]{}},
+		{name: "SubspaceSolver", solver: SubspaceSolver[float64]{}},
+	}
+
+	// every solver returns exactly the same vectors, not only up to their signs
+	for _, tt := range solvers {
+		result, err := tt.solver.Solve(A, Options{})
+		if err != nil {
+			t.Fatalf("%s.Solve(): returned error %v", tt.name, err)
+		}
+		for i := range want.Vectors.Data {
+			if math.Abs(result.Vectors.Data[i]-want.Vectors.Data[i]) > EPSILON {
+				t.Fatalf("%s.Solve(): at index %d, got %f, JacobiSolver gives %f", tt.name, i, result.Vectors.Data[i], want.Vectors.Data[i])
+			}
+		}
+	}
+
+	// the right singular vectors of the data are the eigenvectors of AᵀA with the same signs
+	_, _, VT, err := SVD(data, true)
+	if err != nil {
+		t.Fatalf("SVD(): returned error %v", err)
+	}
+	V := m.Transpose(VT)
+	for i := range want.Vectors.Data {
+		if math.Abs(V.Data[i]-want.Vectors.Data[i]) > EPSILON {
+			t.Fatalf("SVD(): at index %d, got %f, JacobiSolver gives %f", i, V.Data[i], want.Vectors.Data[i])
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package qr
+
+import (
+	"math"
+	"sort"
+
+	m "face_recognition/matrix"
+)
+
+// maximum number of sweeps over all column pairs. Jacobi usually converges in under 15
+const maxSweeps = 60
+
+// computes the singular value decomposition A = U * Σ * Vᵀ with one-sided Jacobi rotations.
+// the columns of A are rotated until they are orthogonal, so AᵀA is never formed and
+// small singular values keep their accuracy. Tall matrices are first reduced to a small
+// triangular factor with BlockedQR. With thin set U is m x min(m, n) and Vᵀ is
+// min(m, n) x n, otherwise U is m x m and Vᵀ is n x n. The signs of the singular vectors follow
+// m.CanonicalSigns applied to the columns of V
+// returns U, the singular values in descending order and Vᵀ, or an error if the rotations don't converge
+func SVD[T m.Float](A m.Dense[T], thin bool) (m.Dense[T], []T, m.Dense[T], error) {
+	U, S, VT, err := jacobiSVD(A, thin)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+
+	// a singular vector pair keeps A = U * Σ * Vᵀ when both of its vectors are flipped
+	V := m.Transpose(VT)
+	for i, flipped := range m.CanonicalSigns(V) {
+		if !flipped || i >= U.Cols {
+			continue
+		}
+		col := U.Col(i)
+		for r := range col.Rows {
+			col.Set(r, 0, -col.At(r, 0))
+		}
+	}
+
+	return U, S, m.Transpose(V), nil
+}
+
+// computes the singular value decomposition of SVD with arbitrary signs of the singular vectors
+func jacobiSVD[T m.Float](A m.Dense[T], thin bool) (m.Dense[T], []T, m.Dense[T], error) {
+	if A.Rows < A.Cols {
+		// the decomposition of Aᵀ gives the decomposition of A with U and V swapped
+		U, S, VT, err := jacobiSVD(m.Transpose(A), thin)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		return m.Transpose(VT), S, m.Transpose(U), nil
+	}
+
+	rows, cols := A.Rows, A.Cols
+
+	if rows >= 2*cols {
+		// with A = QR the decomposition of the n x n factor R gives U = Q * U_R, and rotating
+		// the short columns of R is much cheaper than rotating the long columns of A
+		Q, R, err := BlockedQR(A, thin)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		top, err := R.Slice(0, 0, cols, cols)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		UR, S, VT, err := jacobiSVD(top.Copy(), true)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+
+		// the extra columns of the full Q already complete the basis of U
+		firstQ, err := Q.Slice(0, 0, rows, cols)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		firstU, err := m.Multiplication(firstQ.Copy(), UR)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		if err := m.CopyView(firstQ, firstU.View()); err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		return Q, S, VT, nil
+	}
+
+	// the columns of A are stored as rows of W so the rotations read contiguous memory.
+	// the same rotations applied to the identity give the rows of Vᵀ
+	W := m.Transpose(A)
+	VT := m.IdentityOf[T](cols)
+
+	tolerance := T(10 * m.Epsilon[T]())
+	converged := false
+	for range maxSweeps {
+		rotated := false
+		for p := range cols {
+			for q := p + 1; q < cols; q++ {
+				wp, wq := W.Row(p), W.Row(q)
+				alpha := m.Dot(wp, wp)
+				beta := m.Dot(wq, wq)
+				gamma := m.Dot(wp, wq)
+				if gamma == 0 || m.Abs(gamma) <= tolerance*T(math.Sqrt(float64(alpha*beta))) {
+					continue
+				}
+				rotated = true
+
+				// rotation that makes columns p and q orthogonal
+				zeta := (beta - alpha) / (2 * gamma)
+				t := 1 / (m.Abs(zeta) + T(math.Sqrt(float64(1+zeta*zeta))))
+				if zeta < 0 {
+					t = -t
+				}
+				c := 1 / T(math.Sqrt(float64(1+t*t)))
+				s := c * t
+
+				rotate(wp, wq, c, s)
+				rotate(VT.Row(p), VT.Row(q), c, s)
+			}
+		}
+		if !rotated {
+			converged = true
+			break
+		}
+	}
+	if !converged {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, &NotConvergedError{Method: "singular value decomposition", Iterations: maxSweeps}
+	}
+
+	// the singular values are the lengths of the rotated columns
+	S := make([]T, cols)
+	for i := range cols {
+		S[i] = m.Norm2(W.Row(i))
+	}
+	order := make([]int, cols)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return S[order[i]] > S[order[j]]
+	})
+
+	uCols := rows
+	if thin {
+		uCols = cols
+	}
+
+	// the left singular vectors are the normalized columns. They are built as rows of UT
+	UT := m.Dense[T]{
+		Rows: uCols,
+		Cols: rows,
+		Data: make([]T, uCols*rows),
+	}
+	sortedS := make([]T, cols)
+	sortedVT := m.Dense[T]{
+		Rows: cols,
+		Cols: cols,
+		Data: make([]T, cols*cols),
+	}
+	largest := S[order[0]]
+	rank := 0
+	for i, idx := range order {
+		sortedS[i] = S[idx]
+		copy(sortedVT.Row(i), VT.Row(idx))
+		if S[idx] > largest*T(rows)*T(m.Epsilon[T]()) {
+			copy(UT.Row(i), W.Row(idx))
+			m.Normalize(UT.Row(i))
+			rank++
+		}
+	}
+
+	// columns of zero singular values and the extra columns of the full U are
+	// filled with an orthonormal basis of the remaining space
+	completeBasis(UT, rank)
+
+	return m.Transpose(UT), sortedS, sortedVT, nil
+}
+
+// applies a Jacobi rotation to two vectors
+func rotate[T m.Float](x, y []T, c, s T) {
+	for i := range x {
+		xi, yi := x[i], y[i]
+		x[i] = c*xi - s*yi
+		y[i] = s*xi + c*yi
+	}
+}
+
+// fills rows from rank onwards with unit vectors orthogonal to all previous rows.
+// the standard basis vectors are orthogonalized against the rows with Gram-Schmidt
+// and the ones with enough length left are kept. The squared lengths left over sum up
+// to the dimension of the remaining space, so a candidate longer than 1/sqrt(2n) always exists
+func completeBasis[T m.Float](Q m.Dense[T], rank int) {
+	threshold := T(1 / math.Sqrt(2*float64(Q.Cols)))
+	candidate := 0
+	for row := rank; row < Q.Rows; row++ {
+		for candidate < Q.Cols {
+			v := Q.Row(row)
+			clear(v)
+			v[candidate] = 1
+			candidate++
+
+			// orthogonalize twice for accuracy
+			for range 2 {
+				for i := range row {
+					m.Axpy(-m.Dot(Q.Row(i), v), Q.Row(i), v)
+				}
+			}
+			if m.Normalize(v) > threshold {
+				break
+			}
+			clear(v)
+		}
+	}
+}
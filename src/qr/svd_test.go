@@ -0,0 +1,181 @@
+package qr
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func createRandomMatrix(rows, cols int, seed int64) m.Matrix {
+	rng := rand.New(rand.NewSource(seed))
+	data := make([]float64, rows*cols)
+	for i := range data {
+		data[i] = rng.Float64()*2 - 1
+	}
+	return m.Matrix{Rows: rows, Cols: cols, Data: data}
+}
+
+// checks that the columns of Q are orthonormal
+func checkOrthonormalColumns(t *testing.T, name string, Q m.Matrix) {
+	t.Helper()
+	QTQ, _ := m.MulTransA(Q, Q)
+	identity := m.Identity(Q.Cols)
+	for i := range identity.Data {
+		if math.Abs(QTQ.Data[i]-identity.Data[i]) > EPSILON {
+			t.Fatalf("SVD(): columns of %s are not orthonormal, QᵀQ at index %d is %f", name, i, QTQ.Data[i])
+		}
+	}
+}
+
+func TestSVD(t *testing.T) {
+	rankOne, _ := m.Multiplication(createRandomMatrix(6, 1, 5), createRandomMatrix(1, 4, 6))
+	tallRankOne, _ := m.Multiplication(createRandomMatrix(12, 1, 5), createRandomMatrix(1, 4, 6))
+
+	tests := []struct {
+		name   string
+		A      m.Matrix
+		thin   bool
+		wantS  []float64
+		wantU  [2]int
+		wantVT [2]int
+	}{
+		{
+			name:   "known singular values",
+			A:      m.Matrix{Rows: 2, Cols: 2, Data: []float64{3, 0, 4, 5}},
+			thin:   true,
+			wantS:  []float64{math.Sqrt(45), math.Sqrt(5)},
+			wantU:  [2]int{2, 2},
+			wantVT: [2]int{2, 2},
+		},
+		{
+			name:   "thin decomposition of a tall matrix",
+			A:      createRandomMatrix(50, 8, 1),
+			thin:   true,
+			wantU:  [2]int{50, 8},
+			wantVT: [2]int{8, 8},
+		},
+		{
+			name:   "full decomposition of a tall matrix",
+			A:      createRandomMatrix(9, 4, 2),
+			thin:   false,
+			wantU:  [2]int{9, 9},
+			wantVT: [2]int{4, 4},
+		},
+		{
+			name:   "thin decomposition of a wide matrix",
+			A:      createRandomMatrix(5, 12, 3),
+			thin:   true,
+			wantU:  [2]int{5, 5},
+			wantVT: [2]int{5, 12},
+		},
+		{
+			name:   "full decomposition of a wide matrix",
+			A:      createRandomMatrix(3, 7, 4),
+			thin:   false,
+			wantU:  [2]int{3, 3},
+			wantVT: [2]int{7, 7},
+		},
+		{
+			name:   "rank deficient matrix",
+			A:      rankOne,
+			thin:   true,
+			wantU:  [2]int{6, 4},
+			wantVT: [2]int{4, 4},
+		},
+		{
+			name:   "full decomposition of a rank deficient tall matrix",
+			A:      tallRankOne,
+			thin:   false,
+			wantU:  [2]int{12, 12},
+			wantVT: [2]int{4, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			U, S, VT, err := SVD(tt.A, tt.thin)
+			if err != nil {
+				t.Fatalf("SVD(): returned error %v", err)
+			}
+
+			if U.Rows != tt.wantU[0] || U.Cols != tt.wantU[1] {
+				t.Fatalf("SVD(): U is %dx%d, want %dx%d", U.Rows, U.Cols, tt.wantU[0], tt.wantU[1])
+			}
+			if VT.Rows != tt.wantVT[0] || VT.Cols != tt.wantVT[1] {
+				t.Fatalf("SVD(): Vᵀ is %dx%d, want %dx%d", VT.Rows, VT.Cols, tt.wantVT[0], tt.wantVT[1])
+			}
+
+			for i := range tt.wantS {
+				if math.Abs(S[i]-tt.wantS[i]) > EPSILON {
+					t.Errorf("SVD(): singular value %d is %f, want %f", i, S[i], tt.wantS[i])
+				}
+			}
+			for i := 1; i < len(S); i++ {
+				if S[i] > S[i-1] {
+					t.Errorf("SVD(): singular values are not in descending order: %v", S)
+				}
+			}
+
+			checkOrthonormalColumns(t, "U", U)
+			checkOrthonormalColumns(t, "V", m.Transpose(VT))
+
+			// A = U * Σ * Vᵀ using the first len(S) columns of U and rows of Vᵀ
+			for i := range tt.A.Rows {
+				for j := range tt.A.Cols {
+					sum := 0.0
+					for k := range S {
+						sum += U.Data[i*U.Cols+k] * S[k] * VT.Data[k*VT.Cols+j]
+					}
+					if math.Abs(sum-tt.A.Data[i*tt.A.Cols+j]) > EPSILON {
+						t.Fatalf("SVD(): UΣVᵀ differs from A at (%d, %d), got %f, want %f", i, j, sum, tt.A.Data[i*tt.A.Cols+j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSVDMatchesEigenvalues(t *testing.T) {
+	// the squared singular values of A are the eigenvalues of AᵀA
+	A := createRandomMatrix(40, 5, 7)
+	_, S, _, err := SVD(A, true)
+	if err != nil {
+		t.Fatalf("SVD(): returned error %v", err)
+	}
+
+	covariance, _ := m.Covariance(A)
+	values, _, _ := QR_algorithm(covariance)
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	slices.Reverse(sorted)
+
+	for i := range S {
+		if math.Abs(S[i]*S[i]-sorted[i]) > 1e-4 {
+			t.Errorf("SVD(): squared singular value %d is %f, eigenvalue is %f", i, S[i]*S[i], sorted[i])
+		}
+	}
+}
+
+func TestSVDCanonicalSigns(t *testing.T) {
+	A := createRandomMatrix(12, 5, 13)
+	negated := A.View().Copy()
+	m.ScaleInPlace(negated, -1)
+
+	U, _, VT, _ := SVD(A, true)
+	negatedU, _, negatedVT, _ := SVD(negated, true)
+
+	// the sign of -A only moves to U since the signs follow V
+	for i := range VT.Data {
+		if math.Abs(VT.Data[i]-negatedVT.Data[i]) > EPSILON {
+			t.Fatalf("SVD(): Vᵀ of -A differs at index %d: got %f, want %f", i, negatedVT.Data[i], VT.Data[i])
+		}
+	}
+	for i := range U.Data {
+		if math.Abs(U.Data[i]+negatedU.Data[i]) > EPSILON {
+			t.Fatalf("SVD(): U of -A differs at index %d: got %f, want %f", i, negatedU.Data[i], -U.Data[i])
+		}
+	}
+}
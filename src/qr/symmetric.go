@@ -0,0 +1,212 @@
+package qr
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errNotSquare = fmt.Errorf("matrix is not square")
+)
+
+// maximum number of implicit QR steps per eigenvalue
+const maxStepsPerEigenvalue = 30
+
+// computes the eigenvalues and eigenvectors of a symmetric matrix. The matrix is first reduced
+// to tridiagonal form with Householder reflections, and the tridiagonal matrix is then diagonalized
+// with implicit QR steps using the Wilkinson shift. Converged eigenvalues are deflated so each step
+// only works on the unreduced part. This needs O(n³) work once instead of on every iteration
+// like QR_algorithm. Only symmetric matrices are supported
+// returns the eigenvalues and a matrix with the corresponding eigenvectors as columns in the same
+// format as QR_algorithm, or an error if the matrix isn't square or the iteration doesn't converge
+func SymmetricQR[T m.Float](A m.Dense[T]) ([]T, m.Dense[T], error) {
+	values, vectors, _, err := symmetricQR(A, Options{})
+	return values, vectors, err
+}
+
+// runs SymmetricQR with the deflation tolerance and step limit of opts
+// returns the eigenvalues, the eigenvectors and the number of QR steps
+func symmetricQR[T m.Float](A m.Dense[T], opts Options) ([]T, m.Dense[T], int, error) {
+	if A.Rows != A.Cols {
+		return nil, m.Dense[T]{}, 0, errNotSquare
+	}
+
+	diag, offDiag, QT := tridiagonalize(A)
+	steps, err := tridiagonalQR(diag, offDiag, QT, opts)
+	if err != nil {
+		return nil, m.Dense[T]{}, 0, err
+	}
+
+	return diag, m.Transpose(QT), steps, nil
+}
+
+// reduces a symmetric matrix to tridiagonal form QᵀAQ with Householder reflections
+// returns the diagonal, the off-diagonal and Qᵀ
+func tridiagonalize[T m.Float](A m.Dense[T]) ([]T, []T, m.Dense[T]) {
+	n := A.Rows
+	work := m.Dense[T]{
+		Rows: n,
+		Cols: n,
+		Data: make([]T, n*n),
+	}
+	copy(work.Data, A.Data)
+	QT := m.IdentityOf[T](n)
+
+	diag := make([]T, n)
+	offDiag := make([]T, max(n-1, 0))
+	v := make([]T, n)
+	p := make([]T, n)
+	w := make([]T, n)
+
+	for k := 0; k+2 < n; k++ {
+		// the column below the diagonal equals the row right of it by symmetry
+		x := work.Row(k)[k+1:]
+		size := len(x)
+		vk := v[:size]
+		copy(vk, x)
+
+		norm := m.Norm2(x)
+		alpha := -norm
+		if x[0] < 0 {
+			alpha = norm
+		}
+		vk[0] -= alpha
+		vNorm := m.Dot(vk, vk)
+		diag[k] = work.Data[k*n+k]
+		offDiag[k] = alpha
+		if vNorm == 0 {
+			// the column is already reduced
+			offDiag[k] = x[0]
+			continue
+		}
+		beta := 2 / vNorm
+
+		// A22 = H A22 H = A22 - v wᵀ - w vᵀ with p = beta A22 v and w = p - (beta pᵀv / 2) v
+		pk, wk := p[:size], w[:size]
+		for i := range size {
+			pk[i] = beta * m.Dot(work.Row(k + 1 + i)[k+1:], vk)
+		}
+		scale := beta * m.Dot(pk, vk) / 2
+		for i := range size {
+			wk[i] = pk[i] - scale*vk[i]
+		}
+		for i := range size {
+			row := work.Row(k + 1 + i)[k+1:]
+			m.Axpy(-vk[i], wk, row)
+			m.Axpy(-wk[i], vk, row)
+		}
+
+		// Qᵀ = H Qᵀ only changes the rows below k
+		clear(w)
+		for i := range size {
+			m.Axpy(vk[i], QT.Row(k+1+i), w)
+		}
+		for i := range size {
+			m.Axpy(-beta*vk[i], w, QT.Row(k+1+i))
+		}
+	}
+
+	if n >= 2 {
+		diag[n-2] = work.Data[(n-2)*n+n-2]
+		offDiag[n-2] = work.Data[(n-2)*n+n-1]
+	}
+	if n >= 1 {
+		diag[n-1] = work.Data[(n-1)*n+n-1]
+	}
+
+	return diag, offDiag, QT
+}
+
+// diagonalizes a symmetric tridiagonal matrix in place with implicit Wilkinson shifted QR steps.
+// the rotations are also applied to the rows of QT so its rows become the eigenvectors.
+// off-diagonal elements below opts.Tolerance relative to their neighbours are deflated and at most
+// opts.MaxIterations steps are taken. The defaults are the machine epsilon and 30 steps per eigenvalue
+// returns the number of steps or an error if the iteration doesn't converge
+func tridiagonalQR[T m.Float](diag, offDiag []T, QT m.Dense[T], opts Options) (int, error) {
+	n := len(diag)
+	eps := T(cmp.Or(opts.Tolerance, m.Epsilon[T]()))
+	maxSteps := cmp.Or(opts.MaxIterations, maxStepsPerEigenvalue*n)
+
+	steps := 0
+	high := n - 1
+	for high > 0 {
+		// off-diagonal elements that are negligible compared to their neighbours split the matrix
+		for i := range high {
+			if m.Abs(offDiag[i]) <= eps*(m.Abs(diag[i])+m.Abs(diag[i+1])) {
+				offDiag[i] = 0
+			}
+		}
+		if offDiag[high-1] == 0 {
+			high--
+			continue
+		}
+
+		low := high - 1
+		for low > 0 && offDiag[low-1] != 0 {
+			low--
+		}
+
+		if steps == maxSteps {
+			return steps, &NotConvergedError{Method: "symmetric QR", Iterations: steps}
+		}
+		steps++
+		implicitQRStep(diag, offDiag, QT, low, high)
+	}
+
+	return steps, nil
+}
+
+// performs one implicit symmetric QR step with the Wilkinson shift on rows low to high of
+// a tridiagonal matrix. The bulge created by the first rotation is chased down the diagonal
+func implicitQRStep[T m.Float](diag, offDiag []T, QT m.Dense[T], low, high int) {
+	// the Wilkinson shift is the eigenvalue of the trailing 2x2 block closer to its last element
+	d := (diag[high-1] - diag[high]) / 2
+	e := offDiag[high-1]
+	sign := T(1)
+	if d < 0 {
+		sign = -1
+	}
+	shift := diag[high] - e*e/(d+sign*T(math.Hypot(float64(d), float64(e))))
+
+	x := diag[low] - shift
+	z := offDiag[low]
+	for k := low; k < high; k++ {
+		c, s := givens(x, z)
+		if k > low {
+			offDiag[k-1] = c*x - s*z
+		}
+
+		// rotate rows and columns k and k+1
+		dk, dk1, ek := diag[k], diag[k+1], offDiag[k]
+		diag[k] = c*c*dk - 2*c*s*ek + s*s*dk1
+		diag[k+1] = s*s*dk + 2*c*s*ek + c*c*dk1
+		offDiag[k] = c*s*(dk-dk1) + (c*c-s*s)*ek
+		if k+1 < high {
+			z = -s * offDiag[k+1]
+			offDiag[k+1] *= c
+			x = offDiag[k]
+		}
+
+		rotate(QT.Row(k), QT.Row(k+1), c, s)
+	}
+}
+
+// computes a Givens rotation with c*a - s*b = r and s*a + c*b = 0
+// returns c and s
+func givens[T m.Float](a, b T) (T, T) {
+	if b == 0 {
+		return 1, 0
+	}
+	if m.Abs(b) > m.Abs(a) {
+		tau := -a / b
+		s := 1 / T(math.Sqrt(float64(1+tau*tau)))
+		return s * tau, s
+	}
+	tau := -b / a
+	c := 1 / T(math.Sqrt(float64(1+tau*tau)))
+	return c, c * tau
+}
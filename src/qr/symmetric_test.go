@@ -0,0 +1,151 @@
+package qr
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestSymmetricQR(t *testing.T) {
+	tests := []struct {
+		name       string
+		A          m.Matrix
+		wantValues []float64
+		wantErr    error
+	}{
+		{
+			name:       "known eigenvalues",
+			A:          m.Matrix{Rows: 3, Cols: 3, Data: []float64{2, -1, 0, -1, 2, -1, 0, -1, 2}},
+			wantValues: []float64{2 + math.Sqrt2, 2, 2 - math.Sqrt2},
+		},
+		{
+			name:       "diagonal matrix",
+			A:          m.Matrix{Rows: 3, Cols: 3, Data: []float64{1, 0, 0, 0, 5, 0, 0, 0, 3}},
+			wantValues: []float64{5, 3, 1},
+		},
+		{
+			name:       "repeated eigenvalues",
+			A:          m.Matrix{Rows: 3, Cols: 3, Data: []float64{2, 1, 1, 1, 2, 1, 1, 1, 2}},
+			wantValues: []float64{4, 1, 1},
+		},
+		{
+			name:       "single element",
+			A:          m.Matrix{Rows: 1, Cols: 1, Data: []float64{7}},
+			wantValues: []float64{7},
+		},
+		{
+			name: "covariance of random data",
+			A:    m.Gram(createRandomMatrix(100, 60, 1)),
+		},
+		{
+			name:    "non-square matrix fails",
+			A:       m.Matrix{Rows: 2, Cols: 3, Data: []float64{1, 2, 3, 4, 5, 6}},
+			wantErr: errNotSquare,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, vectors, err := SymmetricQR(tt.A)
+			if err != tt.wantErr {
+				t.Fatalf("SymmetricQR(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			sorted := slices.Clone(values)
+			slices.Sort(sorted)
+			slices.Reverse(sorted)
+			for i := range tt.wantValues {
+				if math.Abs(sorted[i]-tt.wantValues[i]) > EPSILON {
+					t.Errorf("SymmetricQR(): eigenvalue %d is %f, want %f", i, sorted[i], tt.wantValues[i])
+				}
+			}
+
+			// every column must satisfy Av = λv and the columns must be orthonormal
+			n := tt.A.Rows
+			AV, _ := m.Multiplication(tt.A, vectors)
+			for i := range n {
+				for j := range n {
+					if math.Abs(AV.Data[i*n+j]-values[j]*vectors.Data[i*n+j]) > EPSILON*math.Max(1, math.Abs(values[0])) {
+						t.Fatalf("SymmetricQR(): Av differs from λv for eigenvalue %d", j)
+					}
+				}
+			}
+			checkOrthonormalColumns(t, "eigenvectors", vectors)
+		})
+	}
+}
+
+func TestSymmetricQRMatchesQR_algorithm(t *testing.T) {
+	A := m.Gram(createRandomMatrix(30, 8, 2))
+
+	values, _, err := SymmetricQR(A)
+	if err != nil {
+		t.Fatalf("SymmetricQR(): returned error %v", err)
+	}
+	wantValues, _, _ := QR_algorithm(A)
+
+	slices.Sort(values)
+	slices.Sort(wantValues)
+	for i := range values {
+		if math.Abs(values[i]-wantValues[i]) > EPSILON {
+			t.Errorf("SymmetricQR(): eigenvalue %d is %f, QR_algorithm gives %f", i, values[i], wantValues[i])
+		}
+	}
+}
+
+func TestTridiagonalize(t *testing.T) {
+	A := m.Gram(createRandomMatrix(12, 6, 3))
+
+	diag, offDiag, QT := tridiagonalize(A)
+	checkOrthonormalColumns(t, "Q", m.Transpose(QT))
+
+	// QᵀAQ must be the tridiagonal matrix
+	QTA, _ := m.Multiplication(QT, A)
+	tridiagonal, _ := m.MulTransB(QTA, QT)
+	n := A.Rows
+	for i := range n {
+		for j := range n {
+			want := 0.0
+			switch {
+			case i == j:
+				want = diag[i]
+			case j == i+1:
+				want = offDiag[i]
+			case i == j+1:
+				want = offDiag[j]
+			}
+			if math.Abs(tridiagonal.Data[i*n+j]-want) > EPSILON {
+				t.Errorf("tridiagonalize(): QᵀAQ at (%d, %d) is %f, want %f", i, j, tridiagonal.Data[i*n+j], want)
+			}
+		}
+	}
+}
+
+func TestGivens(t *testing.T) {
+	tests := []struct {
+		name string
+		a    float64
+		b    float64
+	}{
+		{name: "larger first element", a: 4, b: 3},
+		{name: "larger second element", a: 1, b: -5},
+		{name: "zero second element", a: 2, b: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, s := givens(tt.a, tt.b)
+			if math.Abs(c*c+s*s-1) > EPSILON {
+				t.Errorf("givens(): c² + s² is %f, want 1", c*c+s*s)
+			}
+			if math.Abs(s*tt.a+c*tt.b) > EPSILON {
+				t.Errorf("givens(): rotation leaves %f, want 0", s*tt.a+c*tt.b)
+			}
+		})
+	}
+}
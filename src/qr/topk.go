@@ -0,0 +1,353 @@
+package qr
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errInvalidK = fmt.Errorf("k must be between 1 and the size of the matrix")
+)
+
+// the top-k solvers start from random vectors drawn with this seed so results are reproducible
+const startSeed = 1
+
+// number of extra vectors the top-k solvers keep beyond k. More vectors converge faster
+const (
+	lanczosExtraVectors = 20
+	oversampling        = 10
+)
+
+// default iteration limit of SubspaceIteration
+const maxSubspaceIterations = 1000
+
+// number of power iterations of RandomizedSVD. Each one sharpens the decay of the singular values
+const powerIterations = 2
+
+// computes the k largest eigenvalues and their eigenvectors of a symmetric positive semidefinite
+// matrix, like a covariance matrix, with the Lanczos method. The matrix is only used through
+// products with vectors that build an orthonormal Krylov basis, and the eigenpairs of the small
+// tridiagonal matrix this gives approximate the eigenpairs of A. Every new vector is orthogonalized
+// against the whole basis so the approximations don't get duplicated. The basis grows until the
+// residuals of the k approximations are small, which is usually well before it has n vectors
+// returns the k eigenvalues in descending order and a n x k matrix with the eigenvectors as columns,
+// or an error if the matrix isn't square or k is invalid
+func Lanczos[T m.Float](A m.Dense[T], k int) ([]T, m.Dense[T], error) {
+	values, vectors, _, err := lanczos(A, k, Options{})
+	return values, vectors, err
+}
+
+// runs Lanczos until the residuals are below opts.Tolerance relative to the largest eigenvalue.
+// opts.MaxIterations limits the size of the basis, and without a limit the basis can grow to n
+// vectors where the result is exact
+// returns the eigenvalues, the eigenvectors and the number of Lanczos steps
+func lanczos[T m.Float](A m.Dense[T], k int, opts Options) ([]T, m.Dense[T], int, error) {
+	if A.Rows != A.Cols {
+		return nil, m.Dense[T]{}, 0, errNotSquare
+	}
+	n := A.Rows
+	if k < 1 || k > n {
+		return nil, m.Dense[T]{}, 0, errInvalidK
+	}
+	maxSize := min(n, cmp.Or(opts.MaxIterations, n))
+
+	rng := rand.New(rand.NewSource(startSeed))
+	tolerance := T(cmp.Or(opts.Tolerance, math.Sqrt(m.Epsilon[T]())))
+
+	// a new vector shorter than this relative to A means the basis spans an invariant subspace
+	breakdown := T(m.Epsilon[T]()) * A.NormInf() * T(n)
+
+	basis := [][]T{randomUnitVector[T](n, nil, rng)}
+	var alpha, beta []T
+	size := min(maxSize, max(2*k, k+lanczosExtraVectors))
+	for {
+		for len(alpha) < size {
+			j := len(alpha)
+			w := make([]T, n)
+			for i := range n {
+				w[i] = m.Dot(A.Row(i), basis[j])
+			}
+			alpha = append(alpha, m.Dot(w, basis[j]))
+
+			if j+1 == n {
+				beta = append(beta, 0)
+				break
+			}
+
+			// orthogonalize twice against the whole basis since the three-term recurrence alone loses orthogonality
+			for range 2 {
+				for _, v := range basis {
+					m.Axpy(-m.Dot(v, w), v, w)
+				}
+			}
+			norm := m.Normalize(w)
+			if norm <= breakdown || norm == 0 {
+				// the search continues in a new direction
+				norm = 0
+				w = randomUnitVector(n, basis, rng)
+			}
+			beta = append(beta, norm)
+			basis = append(basis, w)
+		}
+
+		// eigenpairs of the tridiagonal matrix
+		diag := make([]T, size)
+		copy(diag, alpha)
+		offDiag := make([]T, size-1)
+		copy(offDiag, beta)
+		YT := m.IdentityOf[T](size)
+		if _, err := tridiagonalQR(diag, offDiag, YT, Options{}); err != nil {
+			return nil, m.Dense[T]{}, 0, err
+		}
+		order := descendingOrder(diag)
+
+		// the residual of an approximation is the last off-diagonal element times the last element of its vector
+		// the basis may be limited to fewer than k vectors
+		converged := size >= k
+		scale := max(m.Abs(diag[order[0]]), m.Abs(diag[order[size-1]]))
+		for _, i := range order[:min(k, size)] {
+			if m.Abs(beta[size-1]*YT.Data[i*size+size-1]) > tolerance*scale {
+				converged = false
+				break
+			}
+		}
+		if converged || size == n {
+			values := make([]T, k)
+			vectors := m.Dense[T]{
+				Rows: n,
+				Cols: k,
+				Data: make([]T, n*k),
+			}
+			for col, i := range order[:k] {
+				values[col] = diag[i]
+				for j, y := range YT.Row(i) {
+					for row, value := range basis[j] {
+						vectors.Data[row*k+col] += y * value
+					}
+				}
+			}
+			return values, vectors, size, nil
+		}
+		if size == maxSize {
+			return nil, m.Dense[T]{}, size, &NotConvergedError{Method: "Lanczos", Iterations: size}
+		}
+
+		size = min(maxSize, 2*size)
+	}
+}
+
+// computes the k largest eigenvalues and their eigenvectors of a symmetric positive semidefinite
+// matrix with block power iteration. A block of k + 10 orthonormal vectors is repeatedly multiplied
+// by A and orthonormalized again, and the eigenpairs of A restricted to the block are used as the
+// approximations (Rayleigh-Ritz). The error shrinks by the ratio of the (k+11):th and k:th eigenvalues
+// on every iteration, so it suits matrices whose eigenvalues decay fast
+// returns the k eigenvalues in descending order and a n x k matrix with the eigenvectors as columns,
+// or an error if the matrix isn't square, k is invalid or the iteration doesn't converge
+func SubspaceIteration[T m.Float](A m.Dense[T], k int) ([]T, m.Dense[T], error) {
+	values, vectors, _, err := subspaceIteration(A, k, Options{})
+	return values, vectors, err
+}
+
+// runs block power iteration until the residuals are below opts.Tolerance relative to the
+// largest eigenvalue or opts.MaxIterations is reached
+// returns the eigenvalues, the eigenvectors and the number of iterations
+func subspaceIteration[T m.Float](A m.Dense[T], k int, opts Options) ([]T, m.Dense[T], int, error) {
+	if A.Rows != A.Cols {
+		return nil, m.Dense[T]{}, 0, errNotSquare
+	}
+	n := A.Rows
+	if k < 1 || k > n {
+		return nil, m.Dense[T]{}, 0, errInvalidK
+	}
+	maxIter := cmp.Or(opts.MaxIterations, maxSubspaceIterations)
+
+	rng := rand.New(rand.NewSource(startSeed))
+	tolerance := T(cmp.Or(opts.Tolerance, math.Sqrt(m.Epsilon[T]())))
+
+	// the vectors of the block are stored as rows so AQ is computed as QA
+	Q := randomMatrix[T](min(n, k+oversampling), n, rng)
+	orthonormalizeRows(Q, rng)
+	for iteration := range maxIter {
+		Z, err := m.Multiplication(Q, A)
+		if err != nil {
+			return nil, m.Dense[T]{}, 0, err
+		}
+		H, err := m.MulTransB(Z, Q)
+		if err != nil {
+			return nil, m.Dense[T]{}, 0, err
+		}
+		values, Y, err := SymmetricQR(H)
+		if err != nil {
+			return nil, m.Dense[T]{}, 0, err
+		}
+		order := descendingOrder(values)
+
+		// rotate the block to the approximate eigenvectors. Row i of the rotated block belongs to values[i]
+		if Q, err = m.MulTransA(Y, Q); err != nil {
+			return nil, m.Dense[T]{}, 0, err
+		}
+		if Z, err = m.MulTransA(Y, Z); err != nil {
+			return nil, m.Dense[T]{}, 0, err
+		}
+
+		converged := true
+		scale := max(m.Abs(values[order[0]]), m.Abs(values[order[len(order)-1]]))
+		for _, i := range order[:k] {
+			residual := make([]T, n)
+			copy(residual, Z.Row(i))
+			m.Axpy(-values[i], Q.Row(i), residual)
+			if m.Norm2(residual) > tolerance*scale {
+				converged = false
+				break
+			}
+		}
+		if converged {
+			result := make([]T, k)
+			vectors := m.Dense[T]{
+				Rows: n,
+				Cols: k,
+				Data: make([]T, n*k),
+			}
+			for col, i := range order[:k] {
+				result[col] = values[i]
+				for row, value := range Q.Row(i) {
+					vectors.Data[row*k+col] = value
+				}
+			}
+			return result, vectors, iteration + 1, nil
+		}
+
+		Q = Z
+		orthonormalizeRows(Q, rng)
+	}
+
+	return nil, m.Dense[T]{}, maxIter, &NotConvergedError{Method: "subspace iteration", Iterations: maxIter}
+}
+
+// computes the k largest singular values and vectors of A with a randomized range finder.
+// A is multiplied by k + 10 random vectors, which with high probability span the directions
+// of the largest singular values, and two power iterations sharpen that subspace. The small
+// matrix QᵀA projected onto it is then decomposed with SVD. The result is accurate when the
+// singular values decay fast, like they do for face images
+// returns U (m x k), the singular values in descending order and Vᵀ (k x n), or an error if k is invalid
+func RandomizedSVD[T m.Float](A m.Dense[T], k int) (m.Dense[T], []T, m.Dense[T], error) {
+	size := min(A.Rows, A.Cols)
+	if k < 1 || k > size {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, errInvalidK
+	}
+
+	rng := rand.New(rand.NewSource(startSeed))
+
+	// the basis vectors are stored as rows of Qᵀ
+	QT, err := m.MulTransB(randomMatrix[T](min(size, k+oversampling), A.Cols, rng), A)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+	orthonormalizeRows(QT, rng)
+	for range powerIterations {
+		Z, err := m.Multiplication(QT, A)
+		if err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		orthonormalizeRows(Z, rng)
+		if QT, err = m.MulTransB(Z, A); err != nil {
+			return m.Dense[T]{}, nil, m.Dense[T]{}, err
+		}
+		orthonormalizeRows(QT, rng)
+	}
+
+	B, err := m.Multiplication(QT, A)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+	UB, S, VT, err := SVD(B, true)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+	U, err := m.MulTransA(QT, UB)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+
+	firstU, err := U.Slice(0, 0, U.Rows, k)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+	firstVT, err := VT.Slice(0, 0, k, VT.Cols)
+	if err != nil {
+		return m.Dense[T]{}, nil, m.Dense[T]{}, err
+	}
+	return firstU.Copy(), S[:k], firstVT.Copy(), nil
+}
+
+// returns the indices of values in descending order of the values
+func descendingOrder[T m.Float](values []T) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return values[order[a]] > values[order[b]]
+	})
+	return order
+}
+
+// returns a rows x cols matrix with normally distributed elements
+func randomMatrix[T m.Float](rows, cols int, rng *rand.Rand) m.Dense[T] {
+	R := m.Dense[T]{
+		Rows: rows,
+		Cols: cols,
+		Data: make([]T, rows*cols),
+	}
+	for i := range R.Data {
+		R.Data[i] = T(rng.NormFloat64())
+	}
+	return R
+}
+
+// returns a random unit vector of length n that is orthogonal to the given orthonormal vectors
+func randomUnitVector[T m.Float](n int, basis [][]T, rng *rand.Rand) []T {
+	for {
+		v := make([]T, n)
+		for i := range v {
+			v[i] = T(rng.NormFloat64())
+		}
+		for range 2 {
+			for _, u := range basis {
+				m.Axpy(-m.Dot(u, v), u, v)
+			}
+		}
+		// a random vector is almost never in the span of the basis
+		if m.Normalize(v) > T(math.Sqrt(m.Epsilon[T]())) {
+			return v
+		}
+	}
+}
+
+// orthonormalizes the rows of Q in place with modified Gram-Schmidt. Rows that are linearly
+// dependent on the previous ones are replaced with random vectors, so Q must have at most as many
+// rows as columns
+func orthonormalizeRows[T m.Float](Q m.Dense[T], rng *rand.Rand) {
+	basis := make([][]T, 0, Q.Rows)
+	for i := range Q.Rows {
+		row := Q.Row(i)
+		length := m.Norm2(row)
+
+		// orthogonalize twice for accuracy
+		for range 2 {
+			for _, u := range basis {
+				m.Axpy(-m.Dot(u, row), u, row)
+			}
+		}
+		if norm := m.Normalize(row); norm <= T(m.Epsilon[T]())*length || norm == 0 {
+			copy(row, randomUnitVector(Q.Cols, basis, rng))
+		}
+		basis = append(basis, row)
+	}
+}
@@ -0,0 +1,223 @@
+package qr
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+// returns a matrix U * diag(values) * Vᵀ with random orthonormal U (rows x len(values))
+// and V (cols x len(values)). With rows == cols and U == V the matrix is symmetric
+func createMatrixWithSpectrum(rows, cols int, values []float64, symmetric bool) m.Matrix {
+	U, _, _, _ := SVD(createRandomMatrix(rows, len(values), 7), true)
+	V := U
+	if !symmetric {
+		V, _, _, _ = SVD(createRandomMatrix(cols, len(values), 8), true)
+	}
+
+	scaled := U.View().Copy()
+	for i := range scaled.Rows {
+		for j, value := range values {
+			scaled.Data[i*scaled.Cols+j] *= value
+		}
+	}
+	A, _ := m.MulTransB(scaled, V)
+	return A
+}
+
+// returns count values starting from first and shrinking by ratio
+func decayingValues(count int, first, ratio float64) []float64 {
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = first * math.Pow(ratio, float64(i))
+	}
+	return values
+}
+
+func TestTopEigenpairs(t *testing.T) {
+	decaying := decayingValues(60, 100, 0.8)
+	rankThree := append([]float64{9, 4, 1}, make([]float64, 17)...)
+
+	tests := []struct {
+		name       string
+		A          m.Matrix
+		k          int
+		wantValues []float64
+		wantErr    error
+	}{
+		{
+			name:       "decaying eigenvalues",
+			A:          createMatrixWithSpectrum(60, 60, decaying, true),
+			k:          5,
+			wantValues: decaying[:5],
+		},
+		{
+			name:       "rank deficient matrix",
+			A:          createMatrixWithSpectrum(20, 20, rankThree, true),
+			k:          5,
+			wantValues: rankThree[:5],
+		},
+		{
+			name:       "all eigenpairs",
+			A:          m.Matrix{Rows: 3, Cols: 3, Data: []float64{2, -1, 0, -1, 2, -1, 0, -1, 2}},
+			k:          3,
+			wantValues: []float64{2 + math.Sqrt2, 2, 2 - math.Sqrt2},
+		},
+		{
+			name:    "non-square matrix fails",
+			A:       m.Matrix{Rows: 1, Cols: 2, Data: []float64{1, 2}},
+			k:       1,
+			wantErr: errNotSquare,
+		},
+		{
+			name:    "zero k fails",
+			A:       m.Identity(3),
+			k:       0,
+			wantErr: errInvalidK,
+		},
+		{
+			name:    "too large k fails",
+			A:       m.Identity(3),
+			k:       4,
+			wantErr: errInvalidK,
+		},
+	}
+
+	solvers := []struct {
+		name  string
+		solve func(m.Matrix, int) ([]float64, m.Matrix, error)
+	}{
+		{name: "Lanczos", solve: Lanczos[float64]},
+		{name: "SubspaceIteration", solve: SubspaceIteration[float64]},
+	}
+
+	for _, solver := range solvers {
+		for _, tt := range tests {
+			t.Run(solver.name+"/"+tt.name, func(t *testing.T) {
+				values, vectors, err := solver.solve(tt.A, tt.k)
+				if err != tt.wantErr {
+					t.Fatalf("%s(): returned wrong error %v, want %v", solver.name, err, tt.wantErr)
+				}
+				if err != nil {
+					return
+				}
+
+				if len(values) != tt.k || vectors.Rows != tt.A.Rows || vectors.Cols != tt.k {
+					t.Fatalf("%s(): returned %d values and a %dx%d matrix, want %d values", solver.name, len(values), vectors.Rows, vectors.Cols, tt.k)
+				}
+				for i, want := range tt.wantValues {
+					if math.Abs(values[i]-want) > EPSILON {
+						t.Errorf("%s(): eigenvalue %d is %f, want %f", solver.name, i, values[i], want)
+					}
+				}
+
+				AV, _ := m.Multiplication(tt.A, vectors)
+				for i := range AV.Rows {
+					for j := range tt.k {
+						if math.Abs(AV.Data[i*tt.k+j]-values[j]*vectors.Data[i*tt.k+j]) > EPSILON*values[0] {
+							t.Fatalf("%s(): Av differs from λv for eigenvalue %d", solver.name, j)
+						}
+					}
+				}
+				checkOrthonormalColumns(t, "eigenvectors", vectors)
+			})
+		}
+	}
+}
+
+func TestTopEigenpairsFloat32(t *testing.T) {
+	decaying := decayingValues(40, 100, 0.7)
+	A := m.Convert[float32](createMatrixWithSpectrum(40, 40, decaying, true))
+
+	for name, solve := range map[string]func(m.Matrix32, int) ([]float32, m.Matrix32, error){
+		"Lanczos":           Lanczos[float32],
+		"SubspaceIteration": SubspaceIteration[float32],
+	} {
+		values, _, err := solve(A, 4)
+		if err != nil {
+			t.Fatalf("%s(): returned error %v", name, err)
+		}
+		for i := range values {
+			if math.Abs(float64(values[i])-decaying[i]) > 1e-3 {
+				t.Errorf("%s(): eigenvalue %d is %f, want %f", name, i, values[i], decaying[i])
+			}
+		}
+	}
+}
+
+func TestRandomizedSVD(t *testing.T) {
+	decaying := decayingValues(30, 50, 0.6)
+
+	tests := []struct {
+		name    string
+		A       m.Matrix
+		k       int
+		wantS   []float64
+		wantErr error
+	}{
+		{
+			name:  "tall matrix",
+			A:     createMatrixWithSpectrum(80, 40, decaying, false),
+			k:     5,
+			wantS: decaying[:5],
+		},
+		{
+			name:  "wide matrix",
+			A:     m.Transpose(createMatrixWithSpectrum(80, 40, decaying, false)),
+			k:     3,
+			wantS: decaying[:3],
+		},
+		{
+			name:  "all singular values",
+			A:     m.Matrix{Rows: 2, Cols: 2, Data: []float64{3, 0, 4, 5}},
+			k:     2,
+			wantS: []float64{3 * math.Sqrt(5), math.Sqrt(5)},
+		},
+		{
+			name:    "zero k fails",
+			A:       m.Identity(3),
+			k:       0,
+			wantErr: errInvalidK,
+		},
+		{
+			name:    "too large k fails",
+			A:       createRandomMatrix(5, 3, 1),
+			k:       4,
+			wantErr: errInvalidK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			U, S, VT, err := RandomizedSVD(tt.A, tt.k)
+			if err != tt.wantErr {
+				t.Fatalf("RandomizedSVD(): returned wrong error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if U.Rows != tt.A.Rows || U.Cols != tt.k || len(S) != tt.k || VT.Rows != tt.k || VT.Cols != tt.A.Cols {
+				t.Fatalf("RandomizedSVD(): returned wrong sizes U %dx%d, %d values, Vᵀ %dx%d", U.Rows, U.Cols, len(S), VT.Rows, VT.Cols)
+			}
+			for i, want := range tt.wantS {
+				if math.Abs(S[i]-want) > EPSILON {
+					t.Errorf("RandomizedSVD(): singular value %d is %f, want %f", i, S[i], want)
+				}
+			}
+			checkOrthonormalColumns(t, "U", U)
+			checkOrthonormalColumns(t, "V", m.Transpose(VT))
+
+			// Av = σu for every singular triplet
+			AV, _ := m.MulTransB(tt.A, VT)
+			for i := range AV.Rows {
+				for j := range tt.k {
+					if math.Abs(AV.Data[i*tt.k+j]-S[j]*U.Data[i*tt.k+j]) > EPSILON*S[0] {
+						t.Fatalf("RandomizedSVD(): Av differs from σu for singular value %d", j)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package recognition
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errInvalidCode = fmt.Errorf("invalid eigenface code")
+)
+
+// header of an encoded image: magic, image height, image width and coefficient count
+var codeMagic = [4]byte{'E', 'F', 'C', '1'}
+
+const codeHeaderSize = 10
+
+// encodes a 2D image as its first k face space coefficients. The code stores the image size,
+// k and the coefficients as little-endian float32 values, so a 92x112 image compresses to 10 + 4k bytes
+// the same face space is needed to decode the image
+// returns the code or an error if the image has the wrong size or k is invalid
+func (fs FaceSpace) Encode(img m.Matrix, k int) ([]byte, error) {
+	if k < 0 || k > fs.Basis.Cols {
+		return nil, errInvalidKValue
+	}
+
+	coefficients, err := fs.Project(img)
+	if err != nil {
+		return nil, err
+	}
+
+	code := make([]byte, codeHeaderSize+4*k)
+	copy(code, codeMagic[:])
+	binary.LittleEndian.PutUint16(code[4:], uint16(fs.Rows))
+	binary.LittleEndian.PutUint16(code[6:], uint16(fs.Cols))
+	binary.LittleEndian.PutUint16(code[8:], uint16(k))
+	for i := range k {
+		binary.LittleEndian.PutUint32(code[codeHeaderSize+4*i:], math.Float32bits(float32(coefficients.Data[i])))
+	}
+
+	return code, nil
+}
+
+// decodes an image encoded with Encode using the same face space
+// returns the reconstructed image or an error if the code is invalid or was made with a different face space
+func (fs FaceSpace) Decode(code []byte) (m.Matrix, error) {
+	if len(code) < codeHeaderSize || [4]byte(code[:4]) != codeMagic {
+		return m.Matrix{}, errInvalidCode
+	}
+
+	rows := int(binary.LittleEndian.Uint16(code[4:]))
+	cols := int(binary.LittleEndian.Uint16(code[6:]))
+	k := int(binary.LittleEndian.Uint16(code[8:]))
+	if len(code) != codeHeaderSize+4*k {
+		return m.Matrix{}, errInvalidCode
+	}
+	if rows != fs.Rows || cols != fs.Cols {
+		return m.Matrix{}, errWrongImageSize
+	}
+	if k > fs.Basis.Cols {
+		return m.Matrix{}, errInvalidKValue
+	}
+
+	coefficients := m.Matrix{
+		Rows: k,
+		Cols: 1,
+		Data: make([]float64, k),
+	}
+	for i := range k {
+		coefficients.Data[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(code[codeHeaderSize+4*i:])))
+	}
+
+	return fs.FromCoefficients(coefficients), nil
+}
@@ -0,0 +1,152 @@
+package recognition
+
+import (
+	"math"
+	"testing"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	fs, err := NewFaceSpace(testImages, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		image   m.Matrix
+		k       int
+		wantLen int
+		wantErr error
+	}{
+		{
+			name:    "all coefficients",
+			image:   testImages[0],
+			k:       2,
+			wantLen: 18,
+			wantErr: nil,
+		},
+		{
+			name:    "only the first coefficient",
+			image:   testImages[2],
+			k:       1,
+			wantLen: 14,
+			wantErr: nil,
+		},
+		{
+			name:    "too high k value fails",
+			image:   testImages[0],
+			k:       3,
+			wantLen: 0,
+			wantErr: errInvalidKValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := fs.Encode(tt.image, tt.k)
+			if err != tt.wantErr {
+				t.Fatalf("Encode(): returned wrong error: %v, want %v", err, tt.wantErr)
+			}
+			if len(code) != tt.wantLen {
+				t.Errorf("Encode(): returned %d bytes, want %d", len(code), tt.wantLen)
+			}
+			if err != nil {
+				return
+			}
+
+			decoded, err := fs.Decode(code)
+			if err != nil {
+				t.Fatalf("Decode(): returned error: %v", err)
+			}
+			want, _ := fs.Reconstruct(tt.image, tt.k)
+			for i := range want.Data {
+				// coefficients are stored as float32
+				if math.Abs(decoded.Data[i]-want.Data[i]) > 1e-4 {
+					t.Errorf("Decode(): at index %d, got %f, want %f", i, decoded.Data[i], want.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	fs, err := NewFaceSpace(testImages, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewFaceSpace([]m.Matrix{
+		{Rows: 1, Cols: 4, Data: []float64{4, 5, 1, 2}},
+		{Rows: 1, Cols: 4, Data: []float64{4, 1, 2, 9}},
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := fs.Encode(testImages[0], 2)
+	otherCode, _ := other.Encode(m.Matrix{Rows: 1, Cols: 4, Data: []float64{1, 2, 3, 4}}, 1)
+
+	tests := []struct {
+		name    string
+		code    []byte
+		wantErr error
+	}{
+		{
+			name:    "empty code fails",
+			code:    nil,
+			wantErr: errInvalidCode,
+		},
+		{
+			name:    "truncated code fails",
+			code:    code[:len(code)-1],
+			wantErr: errInvalidCode,
+		},
+		{
+			name:    "code from a face space with another image size fails",
+			code:    otherCode,
+			wantErr: errWrongImageSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fs.Decode(tt.code)
+			if err != tt.wantErr {
+				t.Errorf("Decode(): returned wrong error: %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconstructionQuality(t *testing.T) {
+	fs, err := LoadFaceSpace([]int{1, 2, 3}, 5, 14, "../")
+	if err != nil {
+		t.Fatal(err)
+	}
+	face, err := image.LoadPgmImage("../data/s2/3.pgm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// quality must not drop when more eigenfaces are used and every training
+	// image must be reconstructed almost exactly with the full basis
+	previous := 0.0
+	for k := range 15 {
+		reconstructed, err := fs.Reconstruct(*face, k)
+		if err != nil {
+			t.Fatalf("Reconstruct(): returned error: %v", err)
+		}
+		psnr, err := image.PSNR(*face, reconstructed)
+		if err != nil {
+			t.Fatalf("PSNR(): returned error: %v", err)
+		}
+		if psnr < previous-EPSILON {
+			t.Errorf("Reconstruct(): PSNR dropped from %f to %f with k = %d", previous, psnr, k)
+		}
+		previous = psnr
+	}
+	if previous < 40 {
+		t.Errorf("Reconstruct(): PSNR of a training image with the full basis is %f, want at least 40", previous)
+	}
+}
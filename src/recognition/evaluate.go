@@ -0,0 +1,65 @@
+package recognition
+
+import (
+	"fmt"
+
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errInvalidTrainImages = fmt.Errorf("invalid number of training images. Each set must have images left for testing")
+)
+
+// number of images in each set of the data directory
+const imagesPerSet = 10
+
+// measures how well the recognition method of opts identifies people. The model is trained once on
+// the first trainImages images of each data set and every remaining image of the sets is matched
+// against it. A match is correct if the closest training face belongs to the same set
+// returns the fraction of correctly identified images or an error
+func Evaluate(dataSets []int, trainImages, k int, rootDir string, opts Options) (float64, error) {
+	if opts.Float32 {
+		return evaluate[float32](dataSets, trainImages, k, rootDir, opts)
+	}
+	return evaluate[float64](dataSets, trainImages, k, rootDir, opts)
+}
+
+// evaluates the recognition method like Evaluate with the element type T
+// returns the fraction of correctly identified images or an error
+func evaluate[T m.Float](dataSets []int, trainImages, k int, rootDir string, opts Options) (float64, error) {
+	if trainImages < 1 || trainImages >= imagesPerSet {
+		return 0, errInvalidTrainImages
+	}
+
+	faces, sources, err := loadTraining[T](dataSets, trainImages, rootDir, opts)
+	if err != nil {
+		return 0, err
+	}
+	model, projectedFaces, err := train(false, faces, k, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	correct, total := 0, 0
+	for _, set := range dataSets {
+		for i := trainImages + 1; i <= imagesPerSet; i++ {
+			probe, err := loadTestImage([]int{set, i}, rootDir)
+			if err != nil {
+				return 0, err
+			}
+			projected, err := model.projectImage(probe)
+			if err != nil {
+				return 0, err
+			}
+
+			matchIndex, _ := findClosestMatchBy(projected, projectedFaces, model.distance)
+			if dataSets[sources[matchIndex-1]/trainImages] == set {
+				correct++
+			}
+			total++
+		}
+	}
+
+	return float64(correct) / float64(total), nil
+}
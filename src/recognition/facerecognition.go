@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/rand"
 	"strconv"
 	"time"
 
@@ -15,80 +16,247 @@ import (
 // define possible errors
 var (
 	errInvalidKValue = fmt.Errorf("invalid -k value. It must be positive and less than the size of the training data")
+	errExportMethod  = fmt.Errorf("only eigenface models can be exported")
+	errSVDMethod     = fmt.Errorf("the svd and rsvd backends only compute eigenfaces, use an eigen solver backend with kpca and 2dpca")
 )
 
+// trained eigenface model. Mask is the pixel mask the training faces were flattened with
+// and it must be used for every probe projected into the same eigenspace
+type ModelOf[T m.Float] struct {
+	Eigenfaces m.Dense[T]
+	Mean       m.Dense[T]
+	Mask       *image.Mask
+}
+
+// double precision eigenface model
+type Model = ModelOf[float64]
+
+// trained model of a recognition method. The training faces and the probes are compared
+// in the space that the model projects them to
+type recognizer[T m.Float] interface {
+	projectImage(probe m.Matrix) (m.Dense[T], error) // projects a 2D probe with the size of the training images
+	distance(x, y m.Dense[T]) float64                // distance between two projections
+}
+
+// method used to compute the eigenvectors of the training data
+type Backend int
+
+const (
+	BackendSymmetric     Backend = iota // tridiagonal reduction and shifted QR on the covariance matrix AᵀA of the difference matrix A
+	BackendQR                           // unshifted QR algorithm on the covariance matrix AᵀA
+	BackendSVD                          // singular value decomposition of the difference matrix without forming AᵀA
+	BackendJacobi                       // cyclic Jacobi rotations on the covariance matrix AᵀA
+	BackendLanczos                      // Lanczos iteration for the top k eigenvectors of AᵀA
+	BackendSubspace                     // block power iteration for the top k eigenvectors of AᵀA
+	BackendRandomizedSVD                // randomized singular value decomposition for the top k singular vectors of A
+)
+
+// method used to map the faces into the space where they are matched
+type Method int
+
+const (
+	MethodEigenfaces Method = iota // linear PCA of the pixels
+	MethodKernelPCA                // PCA in the feature space of a nonlinear kernel
+	MethodTwoDPCA                  // 2DPCA of the image matrices without flattening them
+)
+
+// optional settings for the recognition pipeline. The zero value runs plain eigenfaces
+type Options struct {
+	Mask    *image.Mask
+	Augment *image.Augmentation
+	Float32 bool       // train and match in single precision. The faces are converted as they are loaded, so this halves the memory use
+	Backend Backend    // eigenvector computation, tridiagonal QR by default
+	Export  string     // path of a NumPy .npz file that receives the trained model, if set
+	Solver  qr.Options // tolerance and iteration limit of the eigen solver, the count is always k
+	Method  Method     // recognition method, eigenfaces by default
+	Kernel  Kernel     // kernel of MethodKernelPCA
+}
+
 // unit tests ignored since I/O testing wasn't required
-// loads and flattens training images from the data directory for the specified sets and image count per set.
-// Returns a slice of matrices containing the images
-func loadTrainingFaces(dataSets []int, count int, rootDir string) ([]m.Matrix, error) {
-	var faces []m.Matrix
+// loads training images from the data directory for the specified sets and image count per set and
+// passes each of them to add with the index of its source image. When augmentation is enabled augmented
+// copies of each image are passed after the image. Only one image is loaded at a time, so add decides
+// what is kept in memory
+// Returns the first error of loading or add
+func forEachTrainingImage(dataSets []int, count int, rootDir string, opts Options, add func(img m.Matrix, source int) error) error {
+	source := 0
+	var rng *rand.Rand
+	if opts.Augment != nil {
+		rng = rand.New(rand.NewSource(opts.Augment.Seed))
+	}
 
 	for _, set := range dataSets {
 		for i := range count {
 			matrix, err := image.LoadPgmImage(rootDir + "data/s" + strconv.Itoa(set) + "/" + strconv.Itoa(i+1) + ".pgm")
 			if err != nil {
-				return nil, err
+				return err
+			}
+
+			if err := add(*matrix, source); err != nil {
+				return err
 			}
-			flattened := image.FlattenImage(*matrix)
-			faces = append(faces, flattened)
+			if opts.Augment != nil {
+				for _, augmented := range image.Augment(*matrix, *opts.Augment, rng) {
+					if err := add(augmented, source); err != nil {
+						return err
+					}
+				}
+			}
+			source++
 		}
 	}
 
-	return faces, nil
+	return nil
 }
 
-// calculates the eigenfaces and mean face from the training data
-// Returns the eigenfaces matrix and the mean matrix
-func computeEigenfaces(faces []m.Matrix, k int) (m.Matrix, m.Matrix, error) {
-	mean, err := image.MeanOfImages(faces)
+// unit tests ignored since I/O testing wasn't required
+// loads and flattens training images like forEachTrainingImage and converts each of them to T right away,
+// so the double precision images aren't kept when T is float32.
+// pixels outside of the mask are zeroed or dropped when the mask is not nil
+// Returns a slice of matrices containing the images and for each of them the index of the source image
+func loadTrainingFaces[T m.Float](dataSets []int, count int, rootDir string, opts Options) ([]m.Dense[T], []int, error) {
+	var faces []m.Dense[T]
+	var sources []int
+	err := forEachTrainingImage(dataSets, count, rootDir, opts, func(img m.Matrix, source int) error {
+		flattened, err := image.FlattenMasked(img, opts.Mask)
+		if err != nil {
+			return err
+		}
+		faces = append(faces, toPrecision[T](flattened))
+		sources = append(sources, source)
+		return nil
+	})
 	if err != nil {
-		return m.Matrix{}, m.Matrix{}, err
+		return nil, nil, err
 	}
 
-	diffMatrix, err := m.DifferenceMatrix(faces, mean)
+	return faces, sources, nil
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads training images like forEachTrainingImage, zeroes the pixels outside of the mask without
+// flattening the images and converts each of them to T right away
+// Returns a slice of 2D image matrices and for each of them the index of the source image
+func loadMaskedImages[T m.Float](dataSets []int, count int, rootDir string, opts Options) ([]m.Dense[T], []int, error) {
+	var images []m.Dense[T]
+	var sources []int
+	err := forEachTrainingImage(dataSets, count, rootDir, opts, func(img m.Matrix, source int) error {
+		masked, err := image.ApplyMask(img, opts.Mask)
+		if err != nil {
+			return err
+		}
+		images = append(images, toPrecision[T](masked))
+		sources = append(sources, source)
+		return nil
+	})
 	if err != nil {
-		return m.Matrix{}, m.Matrix{}, err
+		return nil, nil, err
 	}
 
-	covariance, err := m.Covariance(diffMatrix)
+	return images, sources, nil
+}
+
+// calculates the eigenfaces and mean face from the training data with the backend and solver settings of opts.
+// all backends find the eigenvectors of AᵀA: the SVD gets them as the right singular vectors of A,
+// which avoids squaring the condition number of A
+// Returns the eigenfaces matrix and the mean matrix, or a *qr.NotConvergedError if the solver runs out of iterations
+func computeEigenfaces[T m.Float](faces []m.Dense[T], k int, opts Options) (m.Dense[T], m.Dense[T], error) {
+	mean, err := image.MeanOfImages(faces)
 	if err != nil {
-		return m.Matrix{}, m.Matrix{}, err
+		return m.Dense[T]{}, m.Dense[T]{}, err
 	}
 
-	eigenvalues, eigenvectors, err := qr.QR_algorithm(covariance)
+	diffMatrix, err := m.DifferenceMatrix(faces, mean)
 	if err != nil {
-		return m.Matrix{}, m.Matrix{}, err
+		return m.Dense[T]{}, m.Dense[T]{}, err
 	}
 
-	sortedVectors := m.SortEigenvectors(eigenvalues, eigenvectors)
+	var sortedVectors m.Dense[T]
+	switch opts.Backend {
+	case BackendSVD:
+		// the singular values are already in descending order
+		_, _, VT, err := qr.SVD(diffMatrix, true)
+		if err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+		sortedVectors = m.Transpose(VT)
+	case BackendRandomizedSVD:
+		_, _, VT, err := qr.RandomizedSVD(diffMatrix, k)
+		if err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+		sortedVectors = m.Transpose(VT)
+	default:
+		covariance, err := m.Covariance(diffMatrix)
+		if err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
 
-	eigenfaces := m.Matrix{
+		// the solver returns the first k eigenvectors in descending order
+		solverOpts := opts.Solver
+		solverOpts.Count = k
+		result, err := eigenSolver[T](opts.Backend).Solve(covariance, solverOpts)
+		if err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+		sortedVectors = result.Vectors
+	}
+
+	eigenfaces := m.Dense[T]{
 		Rows: diffMatrix.Rows,
 		Cols: k,
-		Data: make([]float64, diffMatrix.Rows*k),
+		Data: make([]T, diffMatrix.Rows*k),
 	}
-	for i := range sortedVectors.Rows {
-		for j := range k {
-			eigenfaces.Data[i*k+j] = sortedVectors.Data[i*sortedVectors.Cols+j]
-		}
+	// the first k eigenvectors fill the top rows of the eigenfaces matrix
+	topRows, err := eigenfaces.Slice(0, 0, sortedVectors.Rows, k)
+	if err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
+	}
+	firstVectors, err := sortedVectors.Slice(0, 0, sortedVectors.Rows, k)
+	if err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
+	}
+	if err := m.CopyView(topRows, firstVectors); err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
 	}
 
 	return eigenfaces, mean, nil
 }
 
+// returns the eigen solver that a backend runs on the covariance matrix. The SVD backends
+// have no eigen solver and get the default one, callers reject them where it matters
+func eigenSolver[T m.Float](backend Backend) qr.EigenSolver[T] {
+	switch backend {
+	case BackendQR:
+		return qr.QRSolver[T]{}
+	case BackendJacobi:
+		return qr.JacobiSolver[T]{}
+	case BackendLanczos:
+		return qr.LanczosSolver[T]{}
+	case BackendSubspace:
+		return qr.SubspaceSolver[T]{}
+	}
+	return qr.SymmetricSolver[T]{}
+}
+
 // projects all training faces into the eigenspace defined by eigenfaces and mean
 // Returns a slice of projected face matrices
-func projectFaces(faces []m.Matrix, eigenfaces, mean m.Matrix) ([]m.Matrix, error) {
-	projectedFaces := make([]m.Matrix, len(faces))
-	eigenfaces_T := m.Transpose(eigenfaces)
+func projectFaces[T m.Float](faces []m.Dense[T], eigenfaces, mean m.Dense[T]) ([]m.Dense[T], error) {
+	projectedFaces := make([]m.Dense[T], len(faces))
+
+	// the centered face is only needed for the projection so one buffer is reused for every face
+	centeredFace := m.Dense[T]{
+		Rows: mean.Rows,
+		Cols: mean.Cols,
+		Data: make([]T, len(mean.Data)),
+	}
 
 	for i, face := range faces {
-		centeredFace, err := m.Subraction(face, mean)
-		if err != nil {
+		if err := m.SubractionInto(centeredFace, face, mean); err != nil {
 			return nil, err
 		}
 
-		projected, err := m.Multiplication(eigenfaces_T, centeredFace)
+		projected, err := m.MulTransA(eigenfaces, centeredFace)
 		if err != nil {
 			return nil, err
 		}
@@ -99,41 +267,62 @@ func projectFaces(faces []m.Matrix, eigenfaces, mean m.Matrix) ([]m.Matrix, erro
 }
 
 // unit tests ignored since I/O testing wasn't required
-// loads and projects a test image into the eigenspace using the given eigenfaces and mean
-// Returns the projected test image matrix
-func loadTestImage(eigenfaces, mean m.Matrix, testImageParams []int, rootDir string) (m.Matrix, error) {
+// loads a test image from the data directory
+// Returns the 2D test image matrix
+func loadTestImage(testImageParams []int, rootDir string) (m.Matrix, error) {
 	testImage, err := image.LoadPgmImage(rootDir + "data/s" + strconv.Itoa(testImageParams[0]) + "/" + strconv.Itoa(testImageParams[1]) + ".pgm")
 	if err != nil {
 		return m.Matrix{}, err
 	}
-	flattenedTest := image.FlattenImage(*testImage)
 
-	centeredTest, err := m.Subraction(flattenedTest, mean)
+	return *testImage, nil
+}
+
+// projects a 2D test image into the eigenspace of the model using the mask of the model
+// Returns the projected test image matrix
+func projectTestImage[T m.Float](model ModelOf[T], testImage m.Matrix) (m.Dense[T], error) {
+	flattenedTest, err := image.FlattenMasked(testImage, model.Mask)
+	if err != nil {
+		return m.Dense[T]{}, err
+	}
+
+	centeredTest, err := m.Subraction(toPrecision[T](flattenedTest), model.Mean)
 	if err != nil {
-		return m.Matrix{}, err
+		return m.Dense[T]{}, err
 	}
 
-	projectedTest, err := m.Multiplication(m.Transpose(eigenfaces), centeredTest)
+	projectedTest, err := m.MulTransA(model.Eigenfaces, centeredTest)
 	if err != nil {
-		return m.Matrix{}, err
+		return m.Dense[T]{}, err
 	}
 
 	return projectedTest, nil
 }
 
+// projects a 2D probe image into the eigenspace of the model like projectTestImage
+func (model ModelOf[T]) projectImage(probe m.Matrix) (m.Dense[T], error) {
+	return projectTestImage(model, probe)
+}
+
+// projections into the eigenspace are compared with the Euclidean distance
+func (model ModelOf[T]) distance(x, y m.Dense[T]) float64 {
+	return euclideanDistance(x, y)
+}
+
 // findClosestMatch finds the closest training face to the projected test image
 // Returns the index of the closest match and the minimum distance
-func findClosestMatch(projectedTest m.Matrix, projectedFaces []m.Matrix) (int, float64) {
+func findClosestMatch[T m.Float](projectedTest m.Dense[T], projectedFaces []m.Dense[T]) (int, float64) {
+	return findClosestMatchBy(projectedTest, projectedFaces, euclideanDistance[T])
+}
+
+// finds the closest training face to the projected test image like findClosestMatch with the given distance
+// Returns the index of the closest match and the minimum distance
+func findClosestMatchBy[T m.Float](projectedTest m.Dense[T], projectedFaces []m.Dense[T], distanceOf func(x, y m.Dense[T]) float64) (int, float64) {
 	var minDistance float64 = math.Inf(1)
 	matchIndex := -1
 
 	for i, projectedFace := range projectedFaces {
-		var distance float64
-		for j := range projectedTest.Data {
-			diff := projectedTest.Data[j] - projectedFace.Data[j]
-			distance += diff * diff
-		}
-		distance = math.Sqrt(distance)
+		distance := distanceOf(projectedTest, projectedFace)
 
 		if distance < minDistance {
 			minDistance = distance
@@ -144,6 +333,43 @@ func findClosestMatch(projectedTest m.Matrix, projectedFaces []m.Matrix) (int, f
 	return matchIndex + 1, minDistance
 }
 
+// calculates the Euclidean distance between two projections
+func euclideanDistance[T m.Float](x, y m.Dense[T]) float64 {
+	return math.Sqrt(float64(m.SquaredEuclidean(x.Data, y.Data)))
+}
+
+// unit tests ignored since I/O testing wasn't required
+// saves the eigenfaces, the mean face and the projected training faces into a NumPy .npz file.
+// the projections are stored as the columns of a k x N matrix
+// returns an error if the file can't be written
+func exportModel[T m.Float](path string, model ModelOf[T], projectedFaces []m.Dense[T]) error {
+	projections := m.Dense[T]{
+		Rows: model.Eigenfaces.Cols,
+		Cols: len(projectedFaces),
+		Data: make([]T, model.Eigenfaces.Cols*len(projectedFaces)),
+	}
+	for i, face := range projectedFaces {
+		if err := projections.SetCol(i, face.View()); err != nil {
+			return err
+		}
+	}
+
+	return m.SaveNpz(path, map[string]m.Dense[T]{
+		"eigenfaces":  model.Eigenfaces,
+		"mean":        model.Mean,
+		"projections": projections,
+	})
+}
+
+// converts a double precision matrix to the element type T
+// returns the matrix itself when T is float64 so the default pipeline doesn't copy anything
+func toPrecision[T m.Float](A m.Matrix) m.Dense[T] {
+	if same, ok := any(A).(m.Dense[T]); ok {
+		return same
+	}
+	return m.Convert[T](A)
+}
+
 // converts the minimum distance to a similarity percentage (0-100) using a function (1 - 0.04x) * 100
 func getSimilarity(minDistance float64) float64 {
 	return max((1-(minDistance*0.04))*100.0, 0)
@@ -170,57 +396,183 @@ func timeExecution(name string, timing bool, fn func() error) error {
 // loads training images, computes eigenfaces, projects faces, loads and projects test image
 // finds the closest match, and returns the match index and similarity or a possible error
 func Run(timing bool, dataSets, testImage []int, k, imagesFromEachSet int, rootDir string) (int, float64, error) {
-	if k < 0 || k > len(dataSets)*imagesFromEachSet {
-		return 0, 0.0, errInvalidKValue
+	return RunWithOptions(timing, dataSets, testImage, k, imagesFromEachSet, rootDir, Options{})
+}
+
+// executes the full face recognition pipeline like Run using the given options
+// returns the match index and similarity or a possible error
+func RunWithOptions(timing bool, dataSets, testImage []int, k, imagesFromEachSet int, rootDir string, opts Options) (int, float64, error) {
+	if opts.Float32 {
+		return run[float32](timing, dataSets, testImage, k, imagesFromEachSet, rootDir, opts)
 	}
+	return run[float64](timing, dataSets, testImage, k, imagesFromEachSet, rootDir, opts)
+}
 
-	var (
-		faces          []m.Matrix
-		eigenfaces     m.Matrix
-		mean           m.Matrix
-		projectedFaces []m.Matrix
-		projectedTest  m.Matrix
-		matchIndex     int
-		minDistance    float64
-		similarity     float64
-	)
+// face recognizer that is trained once from the data directory and matches any number of probes against it
+type Recognizer struct {
+	match func(probe m.Matrix) (int, float64, error)
+}
 
-	totalStart := time.Now()
+// unit tests ignored since I/O testing wasn't required
+// trains the recognition method of opts from the data directory like RunWithOptions
+// returns the recognizer or an error
+func NewRecognizer(dataSets []int, k, imagesFromEachSet int, rootDir string, opts Options) (Recognizer, error) {
+	if opts.Float32 {
+		return newRecognizer[float32](dataSets, k, imagesFromEachSet, rootDir, opts)
+	}
+	return newRecognizer[float64](dataSets, k, imagesFromEachSet, rootDir, opts)
+}
 
-	if err := timeExecution("process training images", timing, func() error {
+// unit tests ignored since I/O testing wasn't required
+// trains the recognizer like NewRecognizer with the element type T
+// returns the recognizer or an error
+func newRecognizer[T m.Float](dataSets []int, k, imagesFromEachSet int, rootDir string, opts Options) (Recognizer, error) {
+	faces, sources, err := loadTraining[T](dataSets, imagesFromEachSet, rootDir, opts)
+	if err != nil {
+		return Recognizer{}, err
+	}
+	model, projectedFaces, err := train(false, faces, k, opts)
+	if err != nil {
+		return Recognizer{}, err
+	}
+
+	match := func(probe m.Matrix) (int, float64, error) {
+		projected, err := model.projectImage(probe)
+		if err != nil {
+			return 0, 0.0, err
+		}
+		matchIndex, minDistance := findClosestMatchBy(projected, projectedFaces, model.distance)
+		// augmented templates are reported as the image they were generated from
+		return sources[matchIndex-1] + 1, getSimilarity(minDistance), nil
+	}
+	return Recognizer{match: match}, nil
+}
+
+// finds the closest training image to a 2D probe with the size of the training images
+// returns the match index, counted like the images of RunWithOptions, and the similarity, or an error
+func (rec Recognizer) Match(probe m.Matrix) (int, float64, error) {
+	return rec.match(probe)
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads the training faces in the form the recognition method of opts needs with the element type T.
+// 2DPCA uses the masked 2D images and the other methods the flattened faces
+// Returns the faces and for each of them the index of the source image
+func loadTraining[T m.Float](dataSets []int, count int, rootDir string, opts Options) ([]m.Dense[T], []int, error) {
+	if opts.Method == MethodTwoDPCA {
+		return loadMaskedImages[T](dataSets, count, rootDir, opts)
+	}
+	return loadTrainingFaces[T](dataSets, count, rootDir, opts)
+}
+
+// trains the model of the recognition method of opts with k components and projects the training faces.
+// eigenfaces and kernel PCA have at most one component per training face while 2DPCA has at most one
+// axis per image column. The eigenface model is exported when opts.Export is set, other models can't be exported.
+// the SVD backends decompose the difference matrix of eigenfaces, so the other methods can't use them
+// returns the model and the projected training faces, or an error
+func train[T m.Float](timing bool, faces []m.Dense[T], k int, opts Options) (recognizer[T], []m.Dense[T], error) {
+	if opts.Method != MethodEigenfaces && opts.Export != "" {
+		return nil, nil, errExportMethod
+	}
+	if opts.Method != MethodEigenfaces && (opts.Backend == BackendSVD || opts.Backend == BackendRandomizedSVD) {
+		return nil, nil, errSVDMethod
+	}
+	if opts.Method != MethodTwoDPCA && (k < 0 || k > len(faces)) {
+		return nil, nil, errInvalidKValue
+	}
+
+	var projectedFaces []m.Dense[T]
+	switch opts.Method {
+	case MethodKernelPCA:
+		var model KernelModelOf[T]
+		// the training faces are projected as a by-product of the kernel matrix
+		err := timeExecution("compute kernel PCA", timing, func() error {
+			var err error
+			model, projectedFaces, err = trainKernelPCA(faces, k, opts)
+			return err
+		})
+		return model, projectedFaces, err
+	case MethodTwoDPCA:
+		var model TwoDModelOf[T]
+		err := timeExecution("compute 2DPCA", timing, func() error {
+			var err error
+			model, projectedFaces, err = trainTwoDPCA(faces, k, opts)
+			return err
+		})
+		return model, projectedFaces, err
+	}
+
+	model := ModelOf[T]{Mask: opts.Mask}
+	if err := timeExecution("compute eigenfaces", timing, func() error {
 		var err error
-		faces, err = loadTrainingFaces(dataSets, imagesFromEachSet, rootDir)
+		model.Eigenfaces, model.Mean, err = computeEigenfaces(faces, k, opts)
 		return err
 	}); err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
 
-	if err := timeExecution("compute eigenfaces", timing, func() error {
+	if err := timeExecution("project eigenfaces", timing, func() error {
 		var err error
-		eigenfaces, mean, err = computeEigenfaces(faces, k)
+		projectedFaces, err = projectFaces(faces, model.Eigenfaces, model.Mean)
 		return err
 	}); err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	}
 
-	if err := timeExecution("project eigenfaces", timing, func() error {
+	if opts.Export != "" {
+		if err := exportModel(opts.Export, model, projectedFaces); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return model, projectedFaces, nil
+}
+
+// trains the model of the recognition method from the data directory, projects the test image
+// and finds the closest match. The model is computed with the element type T
+// returns the match index and similarity or a possible error
+func run[T m.Float](timing bool, dataSets, testImage []int, k, imagesFromEachSet int, rootDir string, opts Options) (int, float64, error) {
+	var (
+		faces          []m.Dense[T]
+		sources        []int
+		model          recognizer[T]
+		projectedFaces []m.Dense[T]
+		projectedTest  m.Dense[T]
+		matchIndex     int
+		minDistance    float64
+		similarity     float64
+	)
+
+	totalStart := time.Now()
+
+	if err := timeExecution("process training images", timing, func() error {
 		var err error
-		projectedFaces, err = projectFaces(faces, eigenfaces, mean)
+		faces, sources, err = loadTraining[T](dataSets, imagesFromEachSet, rootDir, opts)
 		return err
 	}); err != nil {
 		log.Fatal(err)
 	}
 
+	model, projectedFaces, err := train(timing, faces, k, opts)
+	if err != nil {
+		return 0, 0.0, err
+	}
+
 	if err := timeExecution("load test image", timing, func() error {
-		var err error
-		projectedTest, err = loadTestImage(eigenfaces, mean, testImage, rootDir)
+		probe, err := loadTestImage(testImage, rootDir)
+		if err != nil {
+			return err
+		}
+		projectedTest, err = model.projectImage(probe)
 		return err
 	}); err != nil {
 		log.Fatal(err)
 	}
 
 	if err := timeExecution("find closest match", timing, func() error {
-		matchIndex, minDistance = findClosestMatch(projectedTest, projectedFaces)
+		matchIndex, minDistance = findClosestMatchBy(projectedTest, projectedFaces, model.distance)
+		// augmented templates are reported as the image they were generated from
+		matchIndex = sources[matchIndex-1] + 1
 		similarity = getSimilarity(minDistance)
 		return nil
 	}); err != nil {
@@ -1,10 +1,14 @@
 package recognition
 
 import (
+	"errors"
 	"math"
+	"math/rand"
 	"testing"
 
+	"face_recognition/image"
 	m "face_recognition/matrix"
+	"face_recognition/qr"
 )
 
 const EPSILON = 1e-6
@@ -52,7 +56,7 @@ func TestComputeEigenfaces(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			eigenfaces, mean, err := computeEigenfaces(tt.faces, tt.k)
+			eigenfaces, mean, err := computeEigenfaces(tt.faces, tt.k, Options{Backend: BackendQR})
 			if err != tt.wantErr {
 				t.Errorf("ComputeEigenfaces(): returned wrong error: %v", err)
 			}
@@ -86,6 +90,53 @@ func TestComputeEigenfaces(t *testing.T) {
 	}
 }
 
+func TestComputeEigenfacesNotConverged(t *testing.T) {
+	faces := []m.Matrix{
+		{Rows: 2, Cols: 2, Data: []float64{4, 5, 1, 2}},
+		{Rows: 2, Cols: 2, Data: []float64{4, 1, 2, 9}},
+		{Rows: 2, Cols: 2, Data: []float64{7, 3, 8, 1}},
+	}
+
+	_, _, err := computeEigenfaces(faces, 2, Options{Backend: BackendJacobi, Solver: qr.Options{MaxIterations: 1}})
+
+	var notConverged *qr.NotConvergedError
+	if !errors.As(err, &notConverged) {
+		t.Fatalf("ComputeEigenfaces(): returned error %v, want NotConvergedError", err)
+	}
+	if notConverged.Iterations != 1 {
+		t.Errorf("ComputeEigenfaces(): error reports %d iterations, want 1", notConverged.Iterations)
+	}
+}
+
+func TestComputeEigenfacesCanonicalSigns(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	faces := make([]m.Matrix, 6)
+	for i := range faces {
+		faces[i] = m.Matrix{Rows: 8, Cols: 1, Data: make([]float64, 8)}
+		for j := range faces[i].Data {
+			faces[i].Data[j] = rng.Float64() * 255
+		}
+	}
+
+	want, _, err := computeEigenfaces(faces, 4, Options{})
+	if err != nil {
+		t.Fatalf("ComputeEigenfaces(): returned error %v", err)
+	}
+
+	// every backend gives exactly the same eigenfaces, so models don't depend on the backend
+	for _, backend := range []Backend{BackendSVD, BackendJacobi, BackendLanczos, BackendSubspace, BackendRandomizedSVD} {
+		eigenfaces, _, err := computeEigenfaces(faces, 4, Options{Backend: backend})
+		if err != nil {
+			t.Fatalf("ComputeEigenfaces(): backend %d returned error %v", backend, err)
+		}
+		for i := range want.Data {
+			if math.Abs(eigenfaces.Data[i]-want.Data[i]) > EPSILON {
+				t.Fatalf("ComputeEigenfaces(): backend %d at index %d: got %f, want %f", backend, i, eigenfaces.Data[i], want.Data[i])
+			}
+		}
+	}
+}
+
 func TestProjectFaces(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -376,3 +427,219 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWithOptions(t *testing.T) {
+	dropMask, _ := image.EllipseMask(112, 92, 0.9, 0.95, true)
+	augmentation := image.DefaultAugmentation(1)
+
+	tests := []struct {
+		name              string
+		dataSets          []int
+		testImage         []int
+		k                 int
+		imagesFromEachSet int
+		opts              Options
+		wantMatchIndex    int
+		wantSimilarity    float64
+		wantErr           error
+	}{
+		{
+			name:              "no options works like Run",
+			dataSets:          []int{1},
+			testImage:         []int{1, 1},
+			k:                 5,
+			imagesFromEachSet: 10,
+			opts:              Options{},
+			wantMatchIndex:    1,
+			wantSimilarity:    100.0,
+			wantErr:           nil,
+		},
+		{
+			name:              "masked training data finds the image in the training data",
+			dataSets:          []int{1, 2},
+			testImage:         []int{2, 4},
+			k:                 5,
+			imagesFromEachSet: 10,
+			opts:              Options{Mask: &dropMask},
+			wantMatchIndex:    14,
+			wantSimilarity:    100.0,
+			wantErr:           nil,
+		},
+		{
+			name:              "augmented templates are reported as their source image",
+			dataSets:          []int{1, 2},
+			testImage:         []int{2, 1},
+			k:                 2,
+			imagesFromEachSet: 1,
+			opts:              Options{Augment: &augmentation},
+			wantMatchIndex:    2,
+			wantSimilarity:    100.0,
+			wantErr:           nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchIndex, similarity, err := RunWithOptions(false, tt.dataSets, tt.testImage, tt.k, tt.imagesFromEachSet, "../", tt.opts)
+			if err != tt.wantErr {
+				t.Errorf("RunWithOptions(): returned wrong error: %v, want %v", err, tt.wantErr)
+			}
+
+			if matchIndex != tt.wantMatchIndex {
+				t.Errorf("RunWithOptions(): returned incorrect matchindex: %v, want %v", matchIndex, tt.wantMatchIndex)
+			}
+
+			if math.Abs(similarity-tt.wantSimilarity) > EPSILON {
+				t.Errorf("RunWithOptions(): returned incorrect similarity: %v, want %v", similarity, tt.wantSimilarity)
+			}
+		})
+	}
+}
+
+func TestRunFloat32(t *testing.T) {
+	// similarities are percentages so single precision may differ slightly from double precision
+	const precisionEpsilon = 0.01
+
+	tests := []struct {
+		name              string
+		dataSets          []int
+		testImage         []int
+		k                 int
+		imagesFromEachSet int
+	}{
+		{
+			name:              "image in the training data",
+			dataSets:          []int{1},
+			testImage:         []int{1, 1},
+			k:                 10,
+			imagesFromEachSet: 10,
+		},
+		{
+			name:              "image not in the training data",
+			dataSets:          []int{2, 3},
+			testImage:         []int{20, 10},
+			k:                 10,
+			imagesFromEachSet: 10,
+		},
+		{
+			name:              "many data sets (8)",
+			dataSets:          []int{1, 2, 3, 4, 5, 6, 7, 8},
+			testImage:         []int{20, 2},
+			k:                 3,
+			imagesFromEachSet: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantIndex, wantSimilarity, err := RunWithOptions(false, tt.dataSets, tt.testImage, tt.k, tt.imagesFromEachSet, "../", Options{})
+			if err != nil {
+				t.Fatalf("RunWithOptions(): returned error: %v", err)
+			}
+
+			matchIndex, similarity, err := RunWithOptions(false, tt.dataSets, tt.testImage, tt.k, tt.imagesFromEachSet, "../", Options{Float32: true})
+			if err != nil {
+				t.Fatalf("RunWithOptions(): float32 returned error: %v", err)
+			}
+
+			if matchIndex != wantIndex {
+				t.Errorf("RunWithOptions(): float32 returned matchindex %v, float64 returned %v", matchIndex, wantIndex)
+			}
+			if math.Abs(similarity-wantSimilarity) > precisionEpsilon {
+				t.Errorf("RunWithOptions(): float32 returned similarity %v, float64 returned %v", similarity, wantSimilarity)
+			}
+		})
+	}
+}
+
+func TestRunSVDBackend(t *testing.T) {
+	// both backends compute the same eigenvectors up to their sign, which doesn't change distances
+	const backendEpsilon = 0.01
+
+	tests := []struct {
+		name              string
+		dataSets          []int
+		testImage         []int
+		k                 int
+		imagesFromEachSet int
+	}{
+		{
+			name:              "image in the training data",
+			dataSets:          []int{1},
+			testImage:         []int{1, 1},
+			k:                 10,
+			imagesFromEachSet: 10,
+		},
+		{
+			name:              "image not in the training data",
+			dataSets:          []int{2, 3},
+			testImage:         []int{20, 10},
+			k:                 10,
+			imagesFromEachSet: 10,
+		},
+		{
+			name:              "fewer eigenfaces than images",
+			dataSets:          []int{4, 5, 6},
+			testImage:         []int{5, 2},
+			k:                 4,
+			imagesFromEachSet: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantIndex, wantSimilarity, err := RunWithOptions(false, tt.dataSets, tt.testImage, tt.k, tt.imagesFromEachSet, "../", Options{})
+			if err != nil {
+				t.Fatalf("RunWithOptions(): returned error: %v", err)
+			}
+
+			matchIndex, similarity, err := RunWithOptions(false, tt.dataSets, tt.testImage, tt.k, tt.imagesFromEachSet, "../", Options{Backend: BackendSVD})
+			if err != nil {
+				t.Fatalf("RunWithOptions(): SVD backend returned error: %v", err)
+			}
+
+			if matchIndex != wantIndex {
+				t.Errorf("RunWithOptions(): SVD backend returned matchindex %v, QR backend returned %v", matchIndex, wantIndex)
+			}
+			if math.Abs(similarity-wantSimilarity) > backendEpsilon {
+				t.Errorf("RunWithOptions(): SVD backend returned similarity %v, QR backend returned %v", similarity, wantSimilarity)
+			}
+		})
+	}
+}
+
+func TestRunBackends(t *testing.T) {
+	// all backends compute the same eigenvectors up to their sign, which doesn't change distances
+	const backendEpsilon = 0.01
+
+	wantIndex, wantSimilarity, err := RunWithOptions(false, []int{2, 3}, []int{20, 10}, 10, 10, "../", Options{})
+	if err != nil {
+		t.Fatalf("RunWithOptions(): returned error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		backend Backend
+	}{
+		{name: "Jacobi", backend: BackendJacobi},
+		{name: "Lanczos", backend: BackendLanczos},
+		{name: "subspace iteration", backend: BackendSubspace},
+		{name: "randomized SVD", backend: BackendRandomizedSVD},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchIndex, similarity, err := RunWithOptions(false, []int{2, 3}, []int{20, 10}, 10, 10, "../", Options{Backend: tt.backend})
+			if err != nil {
+				t.Fatalf("RunWithOptions(): %s backend returned error: %v", tt.name, err)
+			}
+
+			if matchIndex != wantIndex {
+				t.Errorf("RunWithOptions(): %s backend returned matchindex %v, QR backend returned %v", tt.name, matchIndex, wantIndex)
+			}
+			if math.Abs(similarity-wantSimilarity) > backendEpsilon {
+				t.Errorf("RunWithOptions(): %s backend returned similarity %v, QR backend returned %v", tt.name, similarity, wantSimilarity)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package recognition
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+	"face_recognition/qr"
+)
+
+// define possible errors
+var (
+	errNoImages        = fmt.Errorf("no images given")
+	errWrongImageSize  = fmt.Errorf("size of the image doesn't match the face space")
+	errDegenerateSpace = fmt.Errorf("training images don't span k dimensions")
+)
+
+// FaceSpace is an orthonormal eigenface basis in pixel space. Unlike the eigenfaces used for
+// matching, the basis vectors have one value per pixel so images can be projected onto the
+// space and reconstructed back from their coefficients
+type FaceSpace struct {
+	Rows        int       // height of the images
+	Cols        int       // width of the images
+	Basis       m.Matrix  // (Rows*Cols) x k matrix with orthonormal eigenfaces as columns
+	Mean        m.Matrix  // (Rows*Cols) x 1 mean face
+	Eigenvalues []float64 // eigenvalues of the basis vectors in descending order
+}
+
+// computes a face space with k eigenfaces from 2D training images of the same size
+// the eigenvectors v of the small AᵀA matrix are mapped to pixel space with Av / ||Av||
+// returns the face space or an error
+func NewFaceSpace(images []m.Matrix, k int) (FaceSpace, error) {
+	if len(images) == 0 {
+		return FaceSpace{}, errNoImages
+	}
+	if k < 1 || k > len(images) {
+		return FaceSpace{}, errInvalidKValue
+	}
+
+	faces := make([]m.Matrix, len(images))
+	for i, img := range images {
+		if img.Rows != images[0].Rows || img.Cols != images[0].Cols {
+			return FaceSpace{}, errWrongImageSize
+		}
+		faces[i] = image.FlattenImage(img)
+	}
+
+	mean, err := image.MeanOfImages(faces)
+	if err != nil {
+		return FaceSpace{}, err
+	}
+
+	diffMatrix, err := m.DifferenceMatrix(faces, mean)
+	if err != nil {
+		return FaceSpace{}, err
+	}
+
+	covariance, err := m.Covariance(diffMatrix)
+	if err != nil {
+		return FaceSpace{}, err
+	}
+
+	// the solver fixes the signs of the eigenvectors so the basis is the same on every run
+	eigen, err := qr.SymmetricSolver[float64]{}.Solve(covariance, qr.Options{Count: k})
+	if err != nil {
+		return FaceSpace{}, err
+	}
+
+	basis := m.Matrix{
+		Rows: diffMatrix.Rows,
+		Cols: k,
+		Data: make([]float64, diffMatrix.Rows*k),
+	}
+	for j := range k {
+		vector := eigen.Vectors.Col(j)
+		column := basis.Col(j)
+		norm := 0.0
+		for i := range diffMatrix.Rows {
+			sum := 0.0
+			for n, value := range diffMatrix.Row(i) {
+				sum += value * vector.At(n, 0)
+			}
+			column.Set(i, 0, sum)
+			norm += sum * sum
+		}
+
+		norm = math.Sqrt(norm)
+		if norm < 1e-9 {
+			return FaceSpace{}, errDegenerateSpace
+		}
+		for i := range diffMatrix.Rows {
+			column.Set(i, 0, column.At(i, 0)/norm)
+		}
+	}
+
+	return FaceSpace{
+		Rows:        images[0].Rows,
+		Cols:        images[0].Cols,
+		Basis:       basis,
+		Mean:        mean,
+		Eigenvalues: eigen.Values,
+	}, nil
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads count images from each of the data sets and builds a face space with k eigenfaces
+// returns the face space or an error
+func LoadFaceSpace(dataSets []int, count, k int, rootDir string) (FaceSpace, error) {
+	var images []m.Matrix
+	for _, set := range dataSets {
+		for i := range count {
+			img, err := image.LoadPgmImage(rootDir + "data/s" + strconv.Itoa(set) + "/" + strconv.Itoa(i+1) + ".pgm")
+			if err != nil {
+				return FaceSpace{}, err
+			}
+			images = append(images, *img)
+		}
+	}
+
+	return NewFaceSpace(images, k)
+}
+
+// projects a 2D image onto the face space
+// returns a k x 1 matrix of coefficients or an error if the image has the wrong size
+func (fs FaceSpace) Project(img m.Matrix) (m.Matrix, error) {
+	if img.Rows != fs.Rows || img.Cols != fs.Cols {
+		return m.Matrix{}, errWrongImageSize
+	}
+
+	centered, err := m.Subraction(image.FlattenImage(img), fs.Mean)
+	if err != nil {
+		return m.Matrix{}, err
+	}
+
+	return m.MulTransA(fs.Basis, centered)
+}
+
+// reconstructs a 2D image from face space coefficients using the first len(coefficients) eigenfaces
+// returns the reconstructed image
+func (fs FaceSpace) FromCoefficients(coefficients m.Matrix) m.Matrix {
+	k := min(coefficients.Rows, fs.Basis.Cols)
+	result := m.Matrix{
+		Rows: fs.Rows,
+		Cols: fs.Cols,
+		Data: make([]float64, fs.Rows*fs.Cols),
+	}
+
+	for i := range result.Data {
+		sum := fs.Mean.Data[i]
+		for j, value := range fs.Basis.Row(i)[:k] {
+			sum += value * coefficients.Data[j]
+		}
+		result.Data[i] = sum
+	}
+
+	return result
+}
+
+// projects a 2D image onto the first k eigenfaces and back
+// returns the reconstructed image or an error if the image has the wrong size or k is invalid
+func (fs FaceSpace) Reconstruct(img m.Matrix, k int) (m.Matrix, error) {
+	if k < 0 || k > fs.Basis.Cols {
+		return m.Matrix{}, errInvalidKValue
+	}
+
+	coefficients, err := fs.Project(img)
+	if err != nil {
+		return m.Matrix{}, err
+	}
+	coefficients.Rows = k
+	coefficients.Data = coefficients.Data[:k]
+
+	return fs.FromCoefficients(coefficients), nil
+}
+
+// computes the distance from face space (DFFS), the euclidean distance between the image and its
+// reconstruction. Low values mean that the image looks like the training faces
+// returns the distance or an error if the image has the wrong size
+func (fs FaceSpace) DFFS(img m.Matrix) (float64, error) {
+	reconstructed, err := fs.Reconstruct(img, fs.Basis.Cols)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Sqrt(m.SquaredEuclidean(img.Data, reconstructed.Data)), nil
+}
@@ -0,0 +1,128 @@
+package recognition
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+// QR_algorithm stops when the diagonal converges so the eigenvectors are less accurate than EPSILON
+const SOLVER_EPSILON = 1e-3
+
+var testImages = []m.Matrix{
+	{Rows: 2, Cols: 2, Data: []float64{4, 5, 1, 2}},
+	{Rows: 2, Cols: 2, Data: []float64{4, 1, 2, 9}},
+	{Rows: 2, Cols: 2, Data: []float64{0, 3, 6, 1}},
+}
+
+func TestNewFaceSpace(t *testing.T) {
+	tests := []struct {
+		name    string
+		images  []m.Matrix
+		k       int
+		wantErr error
+	}{
+		{
+			name:    "basis is orthonormal with valid inputs",
+			images:  testImages,
+			k:       2,
+			wantErr: nil,
+		},
+		{
+			name:    "too high k value fails",
+			images:  testImages,
+			k:       4,
+			wantErr: errInvalidKValue,
+		},
+		{
+			name:    "k equal to the number of images fails since centered images only span k-1 dimensions",
+			images:  testImages,
+			k:       3,
+			wantErr: errDegenerateSpace,
+		},
+		{
+			name:    "images with different sizes fail",
+			images:  []m.Matrix{testImages[0], {Rows: 1, Cols: 4, Data: []float64{1, 2, 3, 4}}},
+			k:       1,
+			wantErr: errWrongImageSize,
+		},
+		{
+			name:    "no images fails",
+			images:  nil,
+			k:       1,
+			wantErr: errNoImages,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := NewFaceSpace(tt.images, tt.k)
+			if err != tt.wantErr {
+				t.Fatalf("NewFaceSpace(): returned wrong error: %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// checking that BᵀB = I
+			gram, _ := m.Multiplication(m.Transpose(fs.Basis), fs.Basis)
+			identity := m.Identity(tt.k)
+			for i := range gram.Data {
+				if math.Abs(gram.Data[i]-identity.Data[i]) > SOLVER_EPSILON {
+					t.Errorf("NewFaceSpace(): basis isn't orthonormal at index %d: got %f", i, gram.Data[i])
+				}
+			}
+			for i := 1; i < len(fs.Eigenvalues); i++ {
+				if fs.Eigenvalues[i] > fs.Eigenvalues[i-1] {
+					t.Errorf("NewFaceSpace(): eigenvalues aren't in descending order: %v", fs.Eigenvalues)
+				}
+			}
+		})
+	}
+}
+
+func TestReconstructAndDFFS(t *testing.T) {
+	fs, err := NewFaceSpace(testImages, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		image    m.Matrix
+		wantDFFS float64
+		wantErr  error
+	}{
+		{
+			name:     "training image is reconstructed exactly",
+			image:    testImages[1],
+			wantDFFS: 0,
+			wantErr:  nil,
+		},
+		{
+			name:     "mean face is reconstructed exactly",
+			image:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{8.0 / 3, 3, 3, 4}},
+			wantDFFS: 0,
+			wantErr:  nil,
+		},
+		{
+			name:     "image with the wrong size fails",
+			image:    m.Matrix{Rows: 1, Cols: 4, Data: []float64{1, 2, 3, 4}},
+			wantDFFS: 0,
+			wantErr:  errWrongImageSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance, err := fs.DFFS(tt.image)
+			if err != tt.wantErr {
+				t.Errorf("DFFS(): returned wrong error: %v, want %v", err, tt.wantErr)
+			}
+			if math.Abs(distance-tt.wantDFFS) > SOLVER_EPSILON {
+				t.Errorf("DFFS(): got %f, want %f", distance, tt.wantDFFS)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package recognition
+
+import (
+	"fmt"
+	"math"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+)
+
+// define possible errors
+var (
+	errUnknownKernel = fmt.Errorf("unknown kernel type")
+	errKernelDegree  = fmt.Errorf("degree of the polynomial kernel must be positive")
+	errProbeSize     = fmt.Errorf("size of the probe doesn't match the training faces")
+)
+
+// default degree of the polynomial kernel
+const defaultKernelDegree = 2
+
+// kernel function of kernel PCA
+type KernelType int
+
+const (
+	KernelRBF        KernelType = iota // exp(-gamma * ‖x - y‖²)
+	KernelPolynomial                   // (gamma * xᵀy + coef0)^degree
+)
+
+// kernel function and its parameters. The zero value is an RBF kernel with gamma chosen from the training data
+type Kernel struct {
+	Type   KernelType
+	Gamma  float64 // scale of the distances or inner products, 0 chooses it from the training data
+	Degree int     // degree of the polynomial kernel, 0 uses the default of 2
+	Coef0  float64 // constant term of the polynomial kernel
+}
+
+// trained kernel PCA model. Kernel PCA runs PCA in the feature space of the kernel, which is never
+// formed explicitly: everything is computed from kernel values between faces, so the model keeps
+// the training faces to evaluate the kernel between them and a probe
+type KernelModelOf[T m.Float] struct {
+	Kernel       Kernel       // kernel with the gamma that was chosen from the training data
+	Faces        []m.Dense[T] // flattened training faces
+	Coefficients m.Dense[T]   // N x k matrix whose columns express the principal axes in the mapped training faces
+	RowMeans     []float64    // means of the rows of the kernel matrix, needed to center the kernel values of a probe
+	TotalMean    float64      // mean of all elements of the kernel matrix
+	Mask         *image.Mask
+}
+
+// evaluates the kernel between two flattened faces
+func evaluateKernel[T m.Float](kernel Kernel, x, y []T) float64 {
+	if kernel.Type == KernelPolynomial {
+		return math.Pow(kernel.Gamma*float64(m.Dot(x, y))+kernel.Coef0, float64(kernel.Degree))
+	}
+	return math.Exp(-kernel.Gamma * float64(m.SquaredEuclidean(x, y)))
+}
+
+// fills the defaults of the kernel. The default gamma of the RBF kernel is the inverse of the mean
+// squared distance between the faces and the default gamma of the polynomial kernel the inverse of
+// their mean squared norm, which keeps the kernel values around one for any image size and brightness
+// returns the kernel or an error if its type or degree is invalid
+func resolveKernel[T m.Float](kernel Kernel, faces []m.Dense[T]) (Kernel, error) {
+	switch kernel.Type {
+	case KernelRBF:
+	case KernelPolynomial:
+		if kernel.Degree == 0 {
+			kernel.Degree = defaultKernelDegree
+		}
+		if kernel.Degree < 0 {
+			return Kernel{}, errKernelDegree
+		}
+	default:
+		return Kernel{}, errUnknownKernel
+	}
+	if kernel.Gamma != 0 {
+		return kernel, nil
+	}
+
+	sum, count := 0.0, 0
+	for i := range faces {
+		if kernel.Type == KernelPolynomial {
+			sum += float64(m.Dot(faces[i].Data, faces[i].Data))
+			count++
+			continue
+		}
+		for j := range i {
+			sum += float64(m.SquaredEuclidean(faces[i].Data, faces[j].Data))
+			count++
+		}
+	}
+	kernel.Gamma = 1
+	if sum > 0 {
+		kernel.Gamma = float64(count) / sum
+	}
+	return kernel, nil
+}
+
+// centers a kernel matrix in the feature space: Kc = K - 1K - K1 + 1K1 where 1 is the N x N matrix
+// with every element 1/N. This equals the kernel matrix of the mapped faces minus their mean
+// returns the centered matrix, the means of the rows of K and the mean of all elements of K
+func centerKernel(K m.Matrix) (m.Matrix, []float64, float64) {
+	n := K.Rows
+	rowMeans := make([]float64, n)
+	total := 0.0
+	for i := range n {
+		for _, value := range K.Row(i) {
+			rowMeans[i] += value
+		}
+		total += rowMeans[i]
+		rowMeans[i] /= float64(n)
+	}
+	total /= float64(n * n)
+
+	// K is symmetric so the column means equal the row means
+	centered := m.Matrix{
+		Rows: n,
+		Cols: n,
+		Data: make([]float64, n*n),
+	}
+	for i := range n {
+		for j, value := range K.Row(i) {
+			centered.Data[i*n+j] = value - rowMeans[i] - rowMeans[j] + total
+		}
+	}
+	return centered, rowMeans, total
+}
+
+// trains kernel PCA on the flattened faces. The k largest eigenpairs of the centered kernel matrix
+// are computed with the eigen solver of the backend and the eigenvectors are divided by the square
+// root of their eigenvalue, which makes the principal axes in the feature space unit vectors.
+// the centered kernel matrix has rank N - 1 at most, so the axes of zero eigenvalues are left as zero
+// returns the model and the projected training faces, or an error
+func trainKernelPCA[T m.Float](faces []m.Dense[T], k int, opts Options) (KernelModelOf[T], []m.Dense[T], error) {
+	kernel, err := resolveKernel(opts.Kernel, faces)
+	if err != nil {
+		return KernelModelOf[T]{}, nil, err
+	}
+
+	n := len(faces)
+	K := m.Matrix{
+		Rows: n,
+		Cols: n,
+		Data: make([]float64, n*n),
+	}
+	for i := range n {
+		for j := range i + 1 {
+			value := evaluateKernel(kernel, faces[i].Data, faces[j].Data)
+			K.Data[i*n+j] = value
+			K.Data[j*n+i] = value
+		}
+	}
+	centered, rowMeans, total := centerKernel(K)
+	centeredT := toPrecision[T](centered)
+
+	solverOpts := opts.Solver
+	solverOpts.Count = k
+	result, err := eigenSolver[T](opts.Backend).Solve(centeredT, solverOpts)
+	if err != nil {
+		return KernelModelOf[T]{}, nil, err
+	}
+
+	// the solver returns every eigenpair when k is 0
+	vectors, err := result.Vectors.Slice(0, 0, n, k)
+	if err != nil {
+		return KernelModelOf[T]{}, nil, err
+	}
+	coefficients := vectors.Copy()
+	largest := 0.0
+	if k > 0 {
+		largest = float64(result.Values[0])
+	}
+	for j, value := range result.Values[:k] {
+		scale := 0.0
+		if float64(value) > float64(n)*m.Epsilon[T]()*largest {
+			scale = 1 / math.Sqrt(float64(value))
+		}
+		for i := range n {
+			coefficients.Data[i*k+j] *= T(scale)
+		}
+	}
+
+	// the projection of training face i is the transposed coefficients times column i of the centered kernel matrix
+	projections, err := m.MulTransA(coefficients, centeredT)
+	if err != nil {
+		return KernelModelOf[T]{}, nil, err
+	}
+	projectedFaces := make([]m.Dense[T], n)
+	for i := range n {
+		projectedFaces[i] = projections.Col(i).Copy()
+	}
+
+	model := KernelModelOf[T]{
+		Kernel:       kernel,
+		Faces:        faces,
+		Coefficients: coefficients,
+		RowMeans:     rowMeans,
+		TotalMean:    total,
+		Mask:         opts.Mask,
+	}
+	return model, projectedFaces, nil
+}
+
+// projects a flattened face onto the principal axes of the model. The kernel values between the face
+// and the training faces are centered like the kernel matrix and multiplied by the coefficients
+// returns the k x 1 projection or an error if the size of the face doesn't match
+func (model KernelModelOf[T]) project(face m.Dense[T]) (m.Dense[T], error) {
+	n := len(model.Faces)
+	if n == 0 || len(face.Data) != len(model.Faces[0].Data) {
+		return m.Dense[T]{}, errProbeSize
+	}
+
+	values := make([]float64, n)
+	mean := 0.0
+	for i, trainingFace := range model.Faces {
+		values[i] = evaluateKernel(model.Kernel, trainingFace.Data, face.Data)
+		mean += values[i]
+	}
+	mean /= float64(n)
+
+	centered := m.Dense[T]{
+		Rows: n,
+		Cols: 1,
+		Data: make([]T, n),
+	}
+	for i, value := range values {
+		centered.Data[i] = T(value - model.RowMeans[i] - mean + model.TotalMean)
+	}
+
+	return m.MulTransA(model.Coefficients, centered)
+}
+
+// flattens a 2D probe image with the mask of the model and projects it onto the principal axes
+// returns the projection or an error
+func (model KernelModelOf[T]) projectImage(probe m.Matrix) (m.Dense[T], error) {
+	flattened, err := image.FlattenMasked(probe, model.Mask)
+	if err != nil {
+		return m.Dense[T]{}, err
+	}
+	return model.project(toPrecision[T](flattened))
+}
+
+// projections onto the principal axes are compared with the Euclidean distance
+func (model KernelModelOf[T]) distance(x, y m.Dense[T]) float64 {
+	return euclideanDistance(x, y)
+}
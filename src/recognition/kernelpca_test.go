@@ -0,0 +1,270 @@
+package recognition
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+// random flattened faces with pixel values between 0 and 255
+func randomFaces(count, pixels int, seed int64) []m.Matrix {
+	rng := rand.New(rand.NewSource(seed))
+	faces := make([]m.Matrix, count)
+	for i := range faces {
+		faces[i] = m.Matrix{Rows: pixels, Cols: 1, Data: make([]float64, pixels)}
+		for j := range faces[i].Data {
+			faces[i].Data[j] = rng.Float64() * 255
+		}
+	}
+	return faces
+}
+
+func TestEvaluateKernel(t *testing.T) {
+	x := []float64{1, 2, 3}
+	y := []float64{2, 0, 1}
+
+	tests := []struct {
+		name   string
+		kernel Kernel
+		want   float64
+	}{
+		{
+			name:   "RBF kernel",
+			kernel: Kernel{Type: KernelRBF, Gamma: 0.1},
+			want:   math.Exp(-0.1 * 9),
+		},
+		{
+			name:   "quadratic kernel",
+			kernel: Kernel{Type: KernelPolynomial, Gamma: 0.5, Degree: 2, Coef0: 1},
+			want:   math.Pow(0.5*5+1, 2),
+		},
+		{
+			name:   "linear kernel",
+			kernel: Kernel{Type: KernelPolynomial, Gamma: 1, Degree: 1},
+			want:   5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluateKernel(tt.kernel, x, y)
+			if math.Abs(got-tt.want) > EPSILON {
+				t.Errorf("evaluateKernel(): got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveKernel(t *testing.T) {
+	faces := []m.Matrix{
+		{Rows: 2, Cols: 1, Data: []float64{0, 0}},
+		{Rows: 2, Cols: 1, Data: []float64{3, 4}},
+		{Rows: 2, Cols: 1, Data: []float64{0, 4}},
+	}
+
+	tests := []struct {
+		name    string
+		kernel  Kernel
+		want    Kernel
+		wantErr error
+	}{
+		{
+			name:    "RBF gamma is the inverse of the mean squared distance",
+			kernel:  Kernel{},
+			want:    Kernel{Type: KernelRBF, Gamma: 3.0 / (25 + 16 + 9)},
+			wantErr: nil,
+		},
+		{
+			name:    "polynomial gamma is the inverse of the mean squared norm",
+			kernel:  Kernel{Type: KernelPolynomial, Coef0: 1},
+			want:    Kernel{Type: KernelPolynomial, Gamma: 3.0 / (0 + 25 + 16), Degree: defaultKernelDegree, Coef0: 1},
+			wantErr: nil,
+		},
+		{
+			name:    "given parameters are kept",
+			kernel:  Kernel{Type: KernelPolynomial, Gamma: 2, Degree: 3},
+			want:    Kernel{Type: KernelPolynomial, Gamma: 2, Degree: 3},
+			wantErr: nil,
+		},
+		{
+			name:    "negative degree fails",
+			kernel:  Kernel{Type: KernelPolynomial, Degree: -1},
+			want:    Kernel{},
+			wantErr: errKernelDegree,
+		},
+		{
+			name:    "unknown kernel fails",
+			kernel:  Kernel{Type: KernelType(5)},
+			want:    Kernel{},
+			wantErr: errUnknownKernel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveKernel(tt.kernel, faces)
+			if err != tt.wantErr {
+				t.Fatalf("resolveKernel(): returned error %v, want %v", err, tt.wantErr)
+			}
+			if got.Type != tt.want.Type || got.Degree != tt.want.Degree || got.Coef0 != tt.want.Coef0 || math.Abs(got.Gamma-tt.want.Gamma) > EPSILON {
+				t.Errorf("resolveKernel(): got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCenterKernel(t *testing.T) {
+	// the centered kernel matrix of the linear kernel is the Gram matrix of the centered data
+	data := m.Matrix{Rows: 3, Cols: 2, Data: []float64{1, 2, 4, 0, 1, 7}}
+	K, err := m.MulTransB(data, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	centeredData := m.Matrix{Rows: 3, Cols: 2, Data: []float64{-1, -1, 2, -3, -1, 4}}
+	want, err := m.MulTransB(centeredData, centeredData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, rowMeans, total := centerKernel(K)
+	for i := range want.Data {
+		if math.Abs(got.Data[i]-want.Data[i]) > EPSILON {
+			t.Errorf("centerKernel(): at index %d got %v, want %v", i, got.Data[i], want.Data[i])
+		}
+	}
+
+	wantRowMeans := []float64{(5 + 4 + 15) / 3.0, (4 + 16 + 4) / 3.0, (15 + 4 + 50) / 3.0}
+	for i := range wantRowMeans {
+		if math.Abs(rowMeans[i]-wantRowMeans[i]) > EPSILON {
+			t.Errorf("centerKernel(): row mean %d is %v, want %v", i, rowMeans[i], wantRowMeans[i])
+		}
+	}
+	if wantTotal := (24.0 + 24 + 69) / 9; math.Abs(total-wantTotal) > EPSILON {
+		t.Errorf("centerKernel(): total mean is %v, want %v", total, wantTotal)
+	}
+}
+
+func TestKernelPCALinear(t *testing.T) {
+	// with the linear kernel kernel PCA is plain PCA, so with all N - 1 components the projections
+	// keep the distances between the faces and a training face projects to its training projection
+	faces := randomFaces(6, 8, 1)
+	opts := Options{Method: MethodKernelPCA, Kernel: Kernel{Type: KernelPolynomial, Gamma: 1, Degree: 1}}
+
+	for _, backend := range []Backend{BackendSymmetric, BackendJacobi, BackendLanczos} {
+		opts.Backend = backend
+		model, projectedFaces, err := trainKernelPCA(faces, len(faces)-1, opts)
+		if err != nil {
+			t.Fatalf("trainKernelPCA(): backend %d returned error %v", backend, err)
+		}
+
+		for i := range faces {
+			for j := range i {
+				want := math.Sqrt(m.SquaredEuclidean(faces[i].Data, faces[j].Data))
+				got := math.Sqrt(m.SquaredEuclidean(projectedFaces[i].Data, projectedFaces[j].Data))
+				if math.Abs(got-want) > SOLVER_EPSILON {
+					t.Errorf("trainKernelPCA(): backend %d distance between faces %d and %d is %v, want %v", backend, i, j, got, want)
+				}
+			}
+
+			projected, err := model.project(faces[i])
+			if err != nil {
+				t.Fatalf("project(): returned error %v", err)
+			}
+			for l := range projected.Data {
+				if math.Abs(projected.Data[l]-projectedFaces[i].Data[l]) > SOLVER_EPSILON {
+					t.Errorf("project(): backend %d face %d component %d is %v, want %v", backend, i, l, projected.Data[l], projectedFaces[i].Data[l])
+				}
+			}
+		}
+	}
+
+	model, _, err := trainKernelPCA(faces, 2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.project(m.Matrix{Rows: 3, Cols: 1, Data: []float64{1, 2, 3}}); err != errProbeSize {
+		t.Errorf("project(): returned error %v for a probe of the wrong size, want %v", err, errProbeSize)
+	}
+}
+
+func TestKernelPCAZeroEigenvalues(t *testing.T) {
+	// the centered kernel matrix has rank N - 1, so the last component has no axis and stays zero
+	faces := randomFaces(4, 8, 2)
+	_, projectedFaces, err := trainKernelPCA(faces, len(faces), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, projected := range projectedFaces {
+		last := projected.Data[len(faces)-1]
+		if math.IsNaN(last) || math.Abs(last) > EPSILON {
+			t.Errorf("trainKernelPCA(): face %d has %v on the component of the zero eigenvalue, want 0", i, last)
+		}
+	}
+}
+
+// integration test for kernel PCA as the recognition method
+func TestRunKernelPCA(t *testing.T) {
+	tests := []struct {
+		name           string
+		kernel         Kernel
+		testImage      []int
+		wantMatchIndex int
+		wantExact      bool
+	}{
+		{
+			name:           "RBF kernel finds the image in the training data",
+			kernel:         Kernel{},
+			testImage:      []int{2, 5},
+			wantMatchIndex: 5,
+			wantExact:      true,
+		},
+		{
+			name:           "RBF kernel finds the person of an unseen image",
+			kernel:         Kernel{},
+			testImage:      []int{3, 10},
+			wantMatchIndex: 18,
+		},
+		{
+			name:           "polynomial kernel finds the image in the training data",
+			kernel:         Kernel{Type: KernelPolynomial, Degree: 3, Coef0: 1},
+			testImage:      []int{5, 1},
+			wantMatchIndex: 28,
+			wantExact:      true,
+		},
+		{
+			name:           "polynomial kernel finds the person of an unseen image",
+			kernel:         Kernel{Type: KernelPolynomial, Degree: 3, Coef0: 1},
+			testImage:      []int{4, 10},
+			wantMatchIndex: 23,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Method: MethodKernelPCA, Kernel: tt.kernel}
+			matchIndex, similarity, err := RunWithOptions(false, []int{2, 3, 4, 5}, tt.testImage, 10, 9, "../", opts)
+			if err != nil {
+				t.Fatalf("RunWithOptions(): returned error %v", err)
+			}
+
+			if matchIndex != tt.wantMatchIndex {
+				t.Errorf("RunWithOptions(): returned incorrect matchindex: %v, want %v", matchIndex, tt.wantMatchIndex)
+			}
+			if tt.wantExact && math.Abs(similarity-100) > EPSILON {
+				t.Errorf("RunWithOptions(): returned similarity %v for an image in the training data, want 100", similarity)
+			}
+
+			opts.Float32 = true
+			matchIndex32, _, err := RunWithOptions(false, []int{2, 3, 4, 5}, tt.testImage, 10, 9, "../", opts)
+			if err != nil {
+				t.Fatalf("RunWithOptions(): float32 returned error %v", err)
+			}
+			if matchIndex32 != matchIndex {
+				t.Errorf("RunWithOptions(): float32 returned matchindex %v, float64 returned %v", matchIndex32, matchIndex)
+			}
+		})
+	}
+}
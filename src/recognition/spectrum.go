@@ -0,0 +1,227 @@
+package recognition
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+	"face_recognition/qr"
+)
+
+// define possible errors
+var (
+	errSpectrumBackend = fmt.Errorf("the spectrum is always computed in full, use the symmetric, qr, svd or jacobi backend")
+)
+
+// size of the scree plot in pixels and the margins around the plot area
+const (
+	plotWidth  = 720
+	plotHeight = 420
+	plotLeft   = 60
+	plotRight  = 20
+	plotTop    = 40
+	plotBottom = 50
+)
+
+// eigenvalue spectrum of the training data. The eigenvalues of AᵀA equal the nonzero
+// eigenvalues of the pixel covariance AAᵀ, so they tell how much of the variance of the
+// faces each eigenface explains
+type Spectrum struct {
+	Eigenvalues []float64 // eigenvalues in descending order
+	Explained   []float64 // fraction of the total variance explained by each eigenvalue
+	Cumulative  []float64 // fraction of the total variance explained by the first i+1 eigenvalues
+}
+
+// computes the explained and cumulative variance of eigenvalues in descending order.
+// negative eigenvalues only come from rounding and count as zero
+// returns the spectrum
+func NewSpectrum(eigenvalues []float64) Spectrum {
+	s := Spectrum{
+		Eigenvalues: make([]float64, len(eigenvalues)),
+		Explained:   make([]float64, len(eigenvalues)),
+		Cumulative:  make([]float64, len(eigenvalues)),
+	}
+
+	total := 0.0
+	for i, value := range eigenvalues {
+		s.Eigenvalues[i] = max(value, 0)
+		total += s.Eigenvalues[i]
+	}
+	if total == 0 {
+		return s
+	}
+
+	sum := 0.0
+	for i, value := range s.Eigenvalues {
+		sum += value
+		s.Explained[i] = value / total
+		s.Cumulative[i] = sum / total
+	}
+	return s
+}
+
+// unit tests ignored since I/O testing wasn't required
+// loads count images from each of the data sets and computes the spectrum of all their
+// eigenvalues with the backend and solver settings of opts
+// returns the spectrum or an error
+func ComputeSpectrum(dataSets []int, count int, rootDir string, opts Options) (Spectrum, error) {
+	faces, _, err := loadTrainingFaces[float64](dataSets, count, rootDir, opts)
+	if err != nil {
+		return Spectrum{}, err
+	}
+	return spectrumOf(faces, opts)
+}
+
+// computes the spectrum of flattened training faces. Every eigenvalue is needed, so the backends
+// that only compute the first k eigenfaces are rejected instead of running them for all of them
+// returns the spectrum or an error
+func spectrumOf(faces []m.Matrix, opts Options) (Spectrum, error) {
+	switch opts.Backend {
+	case BackendLanczos, BackendSubspace, BackendRandomizedSVD:
+		return Spectrum{}, errSpectrumBackend
+	}
+
+	mean, err := image.MeanOfImages(faces)
+	if err != nil {
+		return Spectrum{}, err
+	}
+	diffMatrix, err := m.DifferenceMatrix(faces, mean)
+	if err != nil {
+		return Spectrum{}, err
+	}
+
+	switch opts.Backend {
+	case BackendSVD:
+		// the eigenvalues of AᵀA are the squared singular values of A
+		_, S, _, err := qr.SVD(diffMatrix, true)
+		if err != nil {
+			return Spectrum{}, err
+		}
+		for i := range S {
+			S[i] *= S[i]
+		}
+		return NewSpectrum(S), nil
+	default:
+		covariance, err := m.Covariance(diffMatrix)
+		if err != nil {
+			return Spectrum{}, err
+		}
+		solverOpts := opts.Solver
+		solverOpts.Count = 0
+		result, err := eigenSolver[float64](opts.Backend).Solve(covariance, solverOpts)
+		if err != nil {
+			return Spectrum{}, err
+		}
+		return NewSpectrum(result.Values), nil
+	}
+}
+
+// returns the smallest number of eigenfaces that explain at least the fraction of the variance
+func (s Spectrum) ComponentsFor(fraction float64) int {
+	for i, value := range s.Cumulative {
+		if value >= fraction {
+			return i + 1
+		}
+	}
+	return len(s.Cumulative)
+}
+
+// writes the spectrum as an aligned table with the variances in percent
+// returns an error if writing fails
+func (s Spectrum) WriteTable(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%4s %16s %10s %11s\n", "k", "eigenvalue", "explained", "cumulative"); err != nil {
+		return err
+	}
+	for i, value := range s.Eigenvalues {
+		if _, err := fmt.Fprintf(w, "%4d %16.6g %9.2f%% %10.2f%%\n", i+1, value, 100*s.Explained[i], 100*s.Cumulative[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writes the spectrum as CSV with a header row and the variances as fractions
+// returns an error if writing fails
+func (s Spectrum) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"component", "eigenvalue", "explained", "cumulative"}); err != nil {
+		return err
+	}
+	for i, value := range s.Eigenvalues {
+		record := []string{
+			strconv.Itoa(i + 1),
+			strconv.FormatFloat(value, 'g', -1, 64),
+			strconv.FormatFloat(s.Explained[i], 'g', -1, 64),
+			strconv.FormatFloat(s.Cumulative[i], 'g', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writes a scree plot of the spectrum as an SVG image. The explained variance of each eigenface
+// is drawn as a bar and the cumulative variance as a line, both in percent on the same axis
+// returns an error if writing fails
+func (s Spectrum) WriteScreePlot(w io.Writer) error {
+	n := len(s.Explained)
+	width := float64(plotWidth - plotLeft - plotRight)
+	height := float64(plotHeight - plotTop - plotBottom)
+	x := func(i float64) float64 {
+		return plotLeft + i*width/float64(max(n, 1))
+	}
+	y := func(fraction float64) float64 {
+		return plotTop + (1-fraction)*height
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", plotWidth, plotHeight, plotWidth, plotHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`+"\n", plotWidth, plotHeight)
+	fmt.Fprintf(&b, `<text x="%d" y="24" text-anchor="middle" font-size="16">Scree plot</text>`+"\n", plotWidth/2)
+
+	// horizontal grid lines and labels every 20 percent
+	for percent := 0; percent <= 100; percent += 20 {
+		level := y(float64(percent) / 100)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`+"\n", plotLeft, level, plotWidth-plotRight, level)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end">%d%%</text>`+"\n", plotLeft-6, level+4, percent)
+	}
+
+	// the component labels are thinned out to at most about ten
+	step := max(1, (n+9)/10)
+	for i := 0; i < n; i += step {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%d</text>`+"\n", x(float64(i)+0.5), plotHeight-plotBottom+18, i+1)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">eigenface</text>`+"\n", plotLeft+int(width)/2, plotHeight-10)
+	fmt.Fprintf(&b, `<text x="16" y="%d" text-anchor="middle" transform="rotate(-90 16 %d)">variance</text>`+"\n", plotTop+int(height)/2, plotTop+int(height)/2)
+
+	barWidth := width / float64(max(n, 1))
+	for i, fraction := range s.Explained {
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#4c78a8"/>`+"\n", x(float64(i))+barWidth*0.1, y(fraction), barWidth*0.8, fraction*height)
+	}
+
+	if n > 0 {
+		points := make([]string, n)
+		for i, fraction := range s.Cumulative {
+			points[i] = fmt.Sprintf("%.1f,%.1f", x(float64(i)+0.5), y(fraction))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#e45756" stroke-width="2"/>`+"\n", strings.Join(points, " "))
+	}
+
+	// axes and legend
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%.1f" stroke="black"/>`+"\n", plotLeft, plotTop, plotLeft, y(0))
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="black"/>`+"\n", plotLeft, y(0), plotWidth-plotRight, y(0))
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="12" height="12" fill="#4c78a8"/>`+"\n", plotWidth-plotRight-170, plotTop+6)
+	fmt.Fprintf(&b, `<text x="%d" y="%d">explained</text>`+"\n", plotWidth-plotRight-152, plotTop+16)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#e45756" stroke-width="2"/>`+"\n", plotWidth-plotRight-170, plotTop+30, plotWidth-plotRight-158, plotTop+30)
+	fmt.Fprintf(&b, `<text x="%d" y="%d">cumulative</text>`+"\n", plotWidth-plotRight-152, plotTop+34)
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,248 @@
+package recognition
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestNewSpectrum(t *testing.T) {
+	tests := []struct {
+		name           string
+		eigenvalues    []float64
+		wantValues     []float64
+		wantExplained  []float64
+		wantCumulative []float64
+	}{
+		{
+			name:           "descending eigenvalues",
+			eigenvalues:    []float64{6, 3, 1},
+			wantValues:     []float64{6, 3, 1},
+			wantExplained:  []float64{0.6, 0.3, 0.1},
+			wantCumulative: []float64{0.6, 0.9, 1},
+		},
+		{
+			name:           "negative eigenvalues count as zero",
+			eigenvalues:    []float64{4, 1e-12, -1e-9},
+			wantValues:     []float64{4, 1e-12, 0},
+			wantExplained:  []float64{1, 0, 0},
+			wantCumulative: []float64{1, 1, 1},
+		},
+		{
+			name:           "all zero eigenvalues",
+			eigenvalues:    []float64{0, 0},
+			wantValues:     []float64{0, 0},
+			wantExplained:  []float64{0, 0},
+			wantCumulative: []float64{0, 0},
+		},
+		{
+			name:           "no eigenvalues",
+			eigenvalues:    []float64{},
+			wantValues:     []float64{},
+			wantExplained:  []float64{},
+			wantCumulative: []float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSpectrum(tt.eigenvalues)
+			for name, pair := range map[string][2][]float64{
+				"eigenvalues": {s.Eigenvalues, tt.wantValues},
+				"explained":   {s.Explained, tt.wantExplained},
+				"cumulative":  {s.Cumulative, tt.wantCumulative},
+			} {
+				got, want := pair[0], pair[1]
+				if len(got) != len(want) {
+					t.Fatalf("NewSpectrum(): %s length = %v, want %v", name, len(got), len(want))
+				}
+				for i := range want {
+					if math.Abs(got[i]-want[i]) > EPSILON {
+						t.Errorf("NewSpectrum(): %s[%v] = %v, want %v", name, i, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestComponentsFor(t *testing.T) {
+	s := NewSpectrum([]float64{6, 3, 1})
+
+	tests := []struct {
+		name     string
+		fraction float64
+		want     int
+	}{
+		{
+			name:     "first eigenvalue is enough",
+			fraction: 0.5,
+			want:     1,
+		},
+		{
+			name:     "exact cumulative fraction",
+			fraction: 0.6,
+			want:     1,
+		},
+		{
+			name:     "two eigenvalues needed",
+			fraction: 0.85,
+			want:     2,
+		},
+		{
+			name:     "all of the variance",
+			fraction: 1,
+			want:     3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.ComponentsFor(tt.fraction); got != tt.want {
+				t.Errorf("ComponentsFor(): got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := NewSpectrum([]float64{0, 0}).ComponentsFor(0.9); got != 2 {
+		t.Errorf("ComponentsFor(): got %v for zero spectrum, want 2", got)
+	}
+}
+
+func TestWriteTableAndCSV(t *testing.T) {
+	s := NewSpectrum([]float64{6, 3, 1})
+
+	var table strings.Builder
+	if err := s.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	wantTable := "" +
+		"   k       eigenvalue  explained  cumulative\n" +
+		"   1                6     60.00%      60.00%\n" +
+		"   2                3     30.00%      90.00%\n" +
+		"   3                1     10.00%     100.00%\n"
+	if table.String() != wantTable {
+		t.Errorf("WriteTable(): got\n%v\nwant\n%v", table.String(), wantTable)
+	}
+
+	var csv strings.Builder
+	if err := s.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	wantCSV := "component,eigenvalue,explained,cumulative\n" +
+		"1,6,0.6,0.6\n" +
+		"2,3,0.3,0.9\n" +
+		"3,1,0.1,1\n"
+	if csv.String() != wantCSV {
+		t.Errorf("WriteCSV(): got\n%v\nwant\n%v", csv.String(), wantCSV)
+	}
+}
+
+func TestWriteScreePlot(t *testing.T) {
+	tests := []struct {
+		name        string
+		eigenvalues []float64
+	}{
+		{
+			name:        "few eigenvalues",
+			eigenvalues: []float64{6, 3, 1},
+		},
+		{
+			name:        "many eigenvalues",
+			eigenvalues: []float64{50, 20, 10, 8, 5, 3, 2, 1, 0.5, 0.3, 0.2, 0.1, 0.05, 0.01},
+		},
+		{
+			name:        "no eigenvalues",
+			eigenvalues: []float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var svg strings.Builder
+			if err := NewSpectrum(tt.eigenvalues).WriteScreePlot(&svg); err != nil {
+				t.Fatal(err)
+			}
+
+			// the plot has to be well-formed XML with one bar per eigenvalue, the background and the
+			// legend as the other rectangles and one polyline for the cumulative variance
+			rects, polylines := 0, 0
+			decoder := xml.NewDecoder(strings.NewReader(svg.String()))
+			for {
+				token, err := decoder.Token()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("WriteScreePlot(): invalid XML: %v", err)
+				}
+				if element, ok := token.(xml.StartElement); ok {
+					switch element.Name.Local {
+					case "rect":
+						rects++
+					case "polyline":
+						polylines++
+					}
+				}
+			}
+
+			if want := len(tt.eigenvalues) + 2; rects != want {
+				t.Errorf("WriteScreePlot(): got %v rectangles, want %v", rects, want)
+			}
+			if want := min(len(tt.eigenvalues), 1); polylines != want {
+				t.Errorf("WriteScreePlot(): got %v polylines, want %v", polylines, want)
+			}
+		})
+	}
+}
+
+func TestSpectrumBackends(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	faces := make([]m.Matrix, 6)
+	for i := range faces {
+		faces[i] = m.Matrix{Rows: 8, Cols: 1, Data: make([]float64, 8)}
+		for j := range faces[i].Data {
+			faces[i].Data[j] = rng.Float64()
+		}
+	}
+
+	want, err := spectrumOf(faces, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want.Eigenvalues) != len(faces) {
+		t.Fatalf("spectrumOf(): got %v eigenvalues, want %v", len(want.Eigenvalues), len(faces))
+	}
+	// the faces are centered so the last eigenvalue is zero and the rest explain all of the variance
+	if got := want.ComponentsFor(1 - EPSILON); got != len(faces)-1 {
+		t.Errorf("spectrumOf(): %v components explain all of the variance, want %v", got, len(faces)-1)
+	}
+
+	for _, backend := range []Backend{BackendQR, BackendSVD, BackendJacobi} {
+		got, err := spectrumOf(faces, Options{Backend: backend})
+		if err != nil {
+			t.Fatalf("spectrumOf(): backend %v: %v", backend, err)
+		}
+		if len(got.Eigenvalues) != len(want.Eigenvalues) {
+			t.Fatalf("spectrumOf(): backend %v got %v eigenvalues, want %v", backend, len(got.Eigenvalues), len(want.Eigenvalues))
+		}
+		for i := range want.Eigenvalues {
+			if math.Abs(got.Eigenvalues[i]-want.Eigenvalues[i]) > SOLVER_EPSILON {
+				t.Errorf("spectrumOf(): backend %v eigenvalue %v = %v, want %v", backend, i, got.Eigenvalues[i], want.Eigenvalues[i])
+			}
+		}
+	}
+
+	// the backends for the first k eigenfaces can't give the full spectrum
+	for _, backend := range []Backend{BackendLanczos, BackendSubspace, BackendRandomizedSVD} {
+		if _, err := spectrumOf(faces, Options{Backend: backend}); err != errSpectrumBackend {
+			t.Errorf("spectrumOf(): backend %v returned error %v, want %v", backend, err, errSpectrumBackend)
+		}
+	}
+}
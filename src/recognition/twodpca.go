@@ -0,0 +1,130 @@
+package recognition
+
+import (
+	"math"
+
+	"face_recognition/image"
+	m "face_recognition/matrix"
+)
+
+// trained 2DPCA model. 2DPCA builds the covariance of the image rows directly from the 2D images
+// instead of flattening them, so the covariance is only width x width (92 x 92 for the data set)
+// and it is estimated well from a few images. Each image is projected to a height x k feature matrix
+type TwoDModelOf[T m.Float] struct {
+	Axes m.Dense[T] // width x k matrix with the projection axes as columns
+	Mean m.Dense[T] // mean image
+	Mask *image.Mask
+}
+
+// computes the image covariance G = 1/M * Σ (Aᵢ - Ā)ᵀ(Aᵢ - Ā) of the 2D training images
+// returns the covariance and the mean image, or an error if the images have different sizes
+func imageCovariance[T m.Float](images []m.Dense[T]) (m.Dense[T], m.Dense[T], error) {
+	mean, err := image.MeanOfImages(images)
+	if err != nil {
+		return m.Dense[T]{}, m.Dense[T]{}, err
+	}
+
+	covariance := m.Dense[T]{
+		Rows: mean.Cols,
+		Cols: mean.Cols,
+		Data: make([]T, mean.Cols*mean.Cols),
+	}
+	// the centered image is only needed for its Gram matrix so one buffer is reused for every image
+	centered := m.Dense[T]{
+		Rows: mean.Rows,
+		Cols: mean.Cols,
+		Data: make([]T, len(mean.Data)),
+	}
+	for _, img := range images {
+		if err := m.SubractionInto(centered, img, mean); err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+		if err := m.AddInPlace(covariance, m.Gram(centered)); err != nil {
+			return m.Dense[T]{}, m.Dense[T]{}, err
+		}
+	}
+	m.ScaleInPlace(covariance, 1/T(len(images)))
+
+	return covariance, mean, nil
+}
+
+// trains 2DPCA on the 2D training images. The projection axes are the k eigenvectors of the image
+// covariance with the largest eigenvalues, computed with the eigen solver of the backend
+// returns the model and the feature matrices of the training images, or an error if k is negative or larger than the image width
+func trainTwoDPCA[T m.Float](images []m.Dense[T], k int, opts Options) (TwoDModelOf[T], []m.Dense[T], error) {
+	covariance, mean, err := imageCovariance(images)
+	if err != nil {
+		return TwoDModelOf[T]{}, nil, err
+	}
+	if k < 0 || k > covariance.Rows {
+		return TwoDModelOf[T]{}, nil, errInvalidKValue
+	}
+
+	solverOpts := opts.Solver
+	solverOpts.Count = k
+	result, err := eigenSolver[T](opts.Backend).Solve(covariance, solverOpts)
+	if err != nil {
+		return TwoDModelOf[T]{}, nil, err
+	}
+	// the solver returns every eigenpair when k is 0
+	axes, err := result.Vectors.Slice(0, 0, covariance.Rows, k)
+	if err != nil {
+		return TwoDModelOf[T]{}, nil, err
+	}
+
+	model := TwoDModelOf[T]{
+		Axes: axes.Copy(),
+		Mean: mean,
+		Mask: opts.Mask,
+	}
+	features := make([]m.Dense[T], len(images))
+	for i, img := range images {
+		features[i], err = model.project(img)
+		if err != nil {
+			return TwoDModelOf[T]{}, nil, err
+		}
+	}
+
+	return model, features, nil
+}
+
+// projects a 2D image onto the axes of the model
+// returns the height x k feature matrix (A - Ā) * X or an error if the size of the image doesn't match
+func (model TwoDModelOf[T]) project(img m.Dense[T]) (m.Dense[T], error) {
+	centered, err := m.Subraction(img, model.Mean)
+	if err != nil {
+		return m.Dense[T]{}, err
+	}
+	return m.Multiplication(centered, model.Axes)
+}
+
+// masks a 2D probe image with the mask of the model and projects it onto the axes
+// returns the feature matrix or an error
+func (model TwoDModelOf[T]) projectImage(probe m.Matrix) (m.Dense[T], error) {
+	masked, err := image.ApplyMask(probe, model.Mask)
+	if err != nil {
+		return m.Dense[T]{}, err
+	}
+	return model.project(toPrecision[T](masked))
+}
+
+// feature matrices are compared with matrixDistance
+func (model TwoDModelOf[T]) distance(x, y m.Dense[T]) float64 {
+	return matrixDistance(x, y)
+}
+
+// calculates the distance between two feature matrices as the sum of the Euclidean distances
+// between their columns. Each column is the projection onto one axis, so unlike the Frobenius
+// distance this doesn't let a single axis with large values dominate the others
+func matrixDistance[T m.Float](A, B m.Dense[T]) float64 {
+	distance := 0.0
+	for j := range A.Cols {
+		sum := 0.0
+		for i := range A.Rows {
+			diff := float64(A.Data[i*A.Cols+j] - B.Data[i*B.Cols+j])
+			sum += diff * diff
+		}
+		distance += math.Sqrt(sum)
+	}
+	return distance
+}
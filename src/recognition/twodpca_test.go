@@ -0,0 +1,249 @@
+package recognition
+
+import (
+	"math"
+	"testing"
+
+	m "face_recognition/matrix"
+)
+
+func TestImageCovariance(t *testing.T) {
+	images := []m.Matrix{
+		{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+		{Rows: 2, Cols: 2, Data: []float64{3, 2, 1, 0}},
+	}
+
+	// the centered images are ±[[-1, 0], [1, 2]], so both add [[2, 2], [2, 4]] to the sum
+	covariance, mean, err := imageCovariance(images)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantMean := []float64{2, 2, 2, 2}
+	for i := range wantMean {
+		if math.Abs(mean.Data[i]-wantMean[i]) > EPSILON {
+			t.Errorf("imageCovariance(): mean at index %d is %v, want %v", i, mean.Data[i], wantMean[i])
+		}
+	}
+	wantCovariance := []float64{2, 2, 2, 4}
+	for i := range wantCovariance {
+		if math.Abs(covariance.Data[i]-wantCovariance[i]) > EPSILON {
+			t.Errorf("imageCovariance(): covariance at index %d is %v, want %v", i, covariance.Data[i], wantCovariance[i])
+		}
+	}
+
+	images = append(images, m.Matrix{Rows: 1, Cols: 4, Data: []float64{1, 2, 3, 4}})
+	if _, _, err := imageCovariance(images); err == nil {
+		t.Errorf("imageCovariance(): images with different sizes didn't fail")
+	}
+}
+
+func TestMatrixDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		A    m.Matrix
+		B    m.Matrix
+		want float64
+	}{
+		{
+			name: "same matrices",
+			A:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			B:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}},
+			want: 0,
+		},
+		{
+			name: "sum of the column distances",
+			A:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{0, 0, 0, 0}},
+			B:    m.Matrix{Rows: 2, Cols: 2, Data: []float64{3, 1, 4, 0}},
+			want: 5 + 1,
+		},
+		{
+			name: "single column is the Euclidean distance",
+			A:    m.Matrix{Rows: 3, Cols: 1, Data: []float64{1, 2, 2}},
+			B:    m.Matrix{Rows: 3, Cols: 1, Data: []float64{0, 0, 0}},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matrixDistance(tt.A, tt.B); math.Abs(got-tt.want) > EPSILON {
+				t.Errorf("matrixDistance(): got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrainTwoDPCA(t *testing.T) {
+	// with all axes the projection is a rotation of the rows, so it keeps the Frobenius distances
+	images := make([]m.Matrix, 5)
+	for i, face := range randomFaces(len(images), 12, 3) {
+		images[i] = m.Matrix{Rows: 4, Cols: 3, Data: face.Data}
+	}
+
+	for _, backend := range []Backend{BackendSymmetric, BackendJacobi, BackendQR} {
+		model, features, err := trainTwoDPCA(images, 3, Options{Backend: backend})
+		if err != nil {
+			t.Fatalf("trainTwoDPCA(): backend %d returned error %v", backend, err)
+		}
+
+		for i := range images {
+			if features[i].Rows != 4 || features[i].Cols != 3 {
+				t.Fatalf("trainTwoDPCA(): feature matrix is %dx%d, want 4x3", features[i].Rows, features[i].Cols)
+			}
+			for j := range i {
+				want := math.Sqrt(m.SquaredEuclidean(images[i].Data, images[j].Data))
+				got := math.Sqrt(m.SquaredEuclidean(features[i].Data, features[j].Data))
+				if math.Abs(got-want) > SOLVER_EPSILON {
+					t.Errorf("trainTwoDPCA(): backend %d distance between images %d and %d is %v, want %v", backend, i, j, got, want)
+				}
+			}
+
+			projected, err := model.projectImage(images[i])
+			if err != nil {
+				t.Fatalf("projectImage(): returned error %v", err)
+			}
+			if distance := model.distance(projected, features[i]); distance > EPSILON {
+				t.Errorf("projectImage(): backend %d image %d is %v away from its training features, want 0", backend, i, distance)
+			}
+		}
+	}
+
+	if _, _, err := trainTwoDPCA(images, 4, Options{}); err != errInvalidKValue {
+		t.Errorf("trainTwoDPCA(): returned error %v for more axes than columns, want %v", err, errInvalidKValue)
+	}
+}
+
+// integration test for 2DPCA as the recognition method
+func TestRunTwoDPCA(t *testing.T) {
+	tests := []struct {
+		name              string
+		dataSets          []int
+		testImage         []int
+		k                 int
+		imagesFromEachSet int
+		backend           Backend
+		wantMatchIndex    int
+		wantExact         bool
+		wantErr           error
+	}{
+		{
+			name:              "finds the image in the training data",
+			dataSets:          []int{2, 3, 4, 5},
+			testImage:         []int{3, 2},
+			k:                 5,
+			imagesFromEachSet: 5,
+			wantMatchIndex:    7,
+			wantExact:         true,
+			wantErr:           nil,
+		},
+		{
+			name:              "finds the person of an unseen image",
+			dataSets:          []int{2, 3, 4, 5},
+			testImage:         []int{4, 8},
+			k:                 5,
+			imagesFromEachSet: 5,
+			wantMatchIndex:    13,
+			wantErr:           nil,
+		},
+		{
+			name:              "more axes than training images",
+			dataSets:          []int{1},
+			testImage:         []int{1, 3},
+			k:                 10,
+			imagesFromEachSet: 5,
+			wantMatchIndex:    3,
+			wantExact:         true,
+			wantErr:           nil,
+		},
+		{
+			name:              "more axes than image columns fails",
+			dataSets:          []int{1},
+			testImage:         []int{1, 3},
+			k:                 93,
+			imagesFromEachSet: 5,
+			wantMatchIndex:    0,
+			wantErr:           errInvalidKValue,
+		},
+		{
+			name:              "svd backend fails",
+			dataSets:          []int{1},
+			testImage:         []int{1, 3},
+			k:                 5,
+			imagesFromEachSet: 5,
+			backend:           BackendSVD,
+			wantMatchIndex:    0,
+			wantErr:           errSVDMethod,
+		},
+		{
+			name:              "randomized svd backend fails",
+			dataSets:          []int{1},
+			testImage:         []int{1, 3},
+			k:                 5,
+			imagesFromEachSet: 5,
+			backend:           BackendRandomizedSVD,
+			wantMatchIndex:    0,
+			wantErr:           errSVDMethod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{Method: MethodTwoDPCA, Backend: tt.backend}
+			matchIndex, similarity, err := RunWithOptions(false, tt.dataSets, tt.testImage, tt.k, tt.imagesFromEachSet, "../", opts)
+			if err != tt.wantErr {
+				t.Fatalf("RunWithOptions(): returned error %v, want %v", err, tt.wantErr)
+			}
+
+			if matchIndex != tt.wantMatchIndex {
+				t.Errorf("RunWithOptions(): returned incorrect matchindex: %v, want %v", matchIndex, tt.wantMatchIndex)
+			}
+			if tt.wantExact && math.Abs(similarity-100) > EPSILON {
+				t.Errorf("RunWithOptions(): returned similarity %v for an image in the training data, want 100", similarity)
+			}
+		})
+	}
+}
+
+// integration test comparing the recognition methods on the images that are left out of training
+func TestEvaluateAgainstEigenfaces(t *testing.T) {
+	dataSets := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	tests := []struct {
+		name         string
+		method       Method
+		wantAccuracy float64
+	}{
+		{
+			name:         "eigenfaces",
+			method:       MethodEigenfaces,
+			wantAccuracy: 0.64,
+		},
+		{
+			name:         "kernel PCA",
+			method:       MethodKernelPCA,
+			wantAccuracy: 0.9,
+		},
+		{
+			name:         "2DPCA",
+			method:       MethodTwoDPCA,
+			wantAccuracy: 0.98,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accuracy, err := Evaluate(dataSets, 5, 5, "../", Options{Method: tt.method})
+			if err != nil {
+				t.Fatalf("Evaluate(): returned error %v", err)
+			}
+			if math.Abs(accuracy-tt.wantAccuracy) > EPSILON {
+				t.Errorf("Evaluate(): returned accuracy %v, want %v", accuracy, tt.wantAccuracy)
+			}
+		})
+	}
+
+	if _, err := Evaluate(dataSets, imagesPerSet, 5, "../", Options{}); err != errInvalidTrainImages {
+		t.Errorf("Evaluate(): returned error %v without test images, want %v", err, errInvalidTrainImages)
+	}
+}